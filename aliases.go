@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"schedulegenerator/db"
+)
+
+// InstructorAlias maps an instructor name from the schedule data to a rate my professor professor
+// Used for names fuzzy matching will never get right (nicknames, name changes)
+type InstructorAlias struct {
+	SchoolId    string `json:"schoolId"`
+	Instructor  string `json:"instructor"` // exactly as it appears in the schedule data
+	ProfessorId int    `json:"professorId"`
+}
+
+// Fetches the instructor aliases of a school
+func fetchInstructorAliases(schoolId string) ([]InstructorAlias, error) {
+	// Get collection from database
+	collection, err := db.GetDBCollection("InstructorAliases")
+
+	if err != nil {
+		fmt.Println(err)
+		return nil, errors.New("unable to fetch collection from database")
+	}
+
+	cursor, err := collection.Find(context.TODO(), bson.M{"schoolid": schoolId})
+	if err != nil {
+		return nil, errors.New("unable to fetch instructor aliases")
+	}
+
+	// Deserialize result
+	var elems []InstructorAlias
+	err = cursor.All(context.TODO(), &elems)
+
+	if err != nil {
+		return nil, errors.New("unable to decode instructor aliases")
+	}
+
+	return elems, nil
+}
+
+// Keys the professor id of each alias by normalized instructor name
+func indexInstructorAliases(aliases []InstructorAlias) map[string]int {
+	index := map[string]int{}
+
+	for _, alias := range aliases {
+		index[normalizeName(alias.Instructor)] = alias.ProfessorId
+	}
+
+	return index
+}
+
+// Adds an instructor alias, replacing the existing one for the same instructor
+func saveInstructorAlias(alias InstructorAlias) error {
+	// Get collection from database
+	collection, err := db.GetDBCollection("InstructorAliases")
+
+	if err != nil {
+		fmt.Println(err)
+		return errors.New("unable to fetch collection from database")
+	}
+
+	opts := options.Replace().SetUpsert(true)
+	_, err = collection.ReplaceOne(context.TODO(), bson.M{"schoolid": alias.SchoolId, "instructor": alias.Instructor}, alias, opts)
+
+	if err != nil {
+		return errors.New("unable to save instructor alias")
+	}
+
+	return nil
+}
+
+// Removes the alias of an instructor
+func deleteInstructorAlias(schoolId string, instructor string) error {
+	// Get collection from database
+	collection, err := db.GetDBCollection("InstructorAliases")
+
+	if err != nil {
+		fmt.Println(err)
+		return errors.New("unable to fetch collection from database")
+	}
+
+	result, err := collection.DeleteOne(context.TODO(), bson.M{"schoolid": schoolId, "instructor": instructor})
+	if err != nil {
+		return errors.New("unable to delete instructor alias")
+	}
+
+	if result.DeletedCount == 0 {
+		return errors.New("did not find specified alias")
+	}
+
+	return nil
+}
+
+// Returns the professor an instructor is aliased to, false if there is no alias or the professor is not in the export
+func aliasedProfessor(instructor string, aliases map[string]int, index *professorIndex) (ProfessorType, bool) {
+	professorId, ok := aliases[normalizeName(instructor)]
+	if !ok {
+		return ProfessorType{}, false
+	}
+
+	return index.professorByID(professorId)
+}
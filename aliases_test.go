@@ -0,0 +1,64 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestIntegrateRatingsWithAliases(t *testing.T) {
+	professors := []ProfessorType{
+		{Id: 1, FirstName: "Robert", LastName: "Smith", OverallRating: 4, TotalRatings: 10},
+		{Id: 2, FirstName: "Maria", LastName: "Garcia", OverallRating: 5, TotalRatings: 10},
+		{Id: 3, FirstName: "Jane", LastName: "Doe", OverallRating: 2, TotalRatings: 10},
+	}
+	aliases := []InstructorAlias{
+		{SchoolId: "pcc", Instructor: "Bob Smith", ProfessorId: 1},       // nickname
+		{SchoolId: "pcc", Instructor: "María Lopez", ProfessorId: 2},     // name change
+		{SchoolId: "pcc", Instructor: "Jane Doe", ProfessorId: 99},       // professor not in the export
+		{SchoolId: "pcc", Instructor: "Unknown Person", ProfessorId: 99}, // neither
+	}
+
+	classes := []Class{
+		{ClassID: "100", CourseName: "MATH 5A", Instructor: "Bob Smith"},
+		{ClassID: "200", CourseName: "MATH 5A", Instructor: "MARIA LOPEZ"},
+		{ClassID: "300", CourseName: "ENGL 1A", Instructor: "Jane Doe"},
+		{ClassID: "400", CourseName: "ENGL 1A", Instructor: "Unknown Person"},
+		{ClassID: "500", CourseName: "ENGL 1A", Instructor: "Alice Walker"},
+	}
+
+	tests := []struct {
+		professorID    int
+		rating         float32
+		fullConfidence bool
+	}{
+		{1, 4, true},   // alias wins over fuzzy matching
+		{2, 5, true},   // aliases ignore case and accents
+		{3, 2, true},   // alias to a missing professor falls back to the name, which matches exactly
+		{0, -1, false}, // unmatched
+		{0, -1, false},
+	}
+
+	enhanced := integrateRatingsIntoClassData("pcc", classes, newProfessorIndex(professors), indexInstructorAliases(aliases))
+	for i, class := range enhanced {
+		want := tests[i]
+		if class.ProfessorID != want.professorID || class.InstructorRating != want.rating || (class.MatchConfidence == 1) != want.fullConfidence {
+			t.Errorf("section %s: professor %d, rating %v, confidence %v, want professor %d, rating %v",
+				class.ClassID, class.ProfessorID, class.InstructorRating, class.MatchConfidence, want.professorID, want.rating)
+		}
+	}
+}
+
+func TestClosestProfessor(t *testing.T) {
+	professors := []ProfessorType{
+		{Id: 1, FirstName: "Robert", LastName: "Smith"},
+		{Id: 2, FirstName: "Maria", LastName: "Garcia"},
+	}
+
+	professor, confidence := closestProfessor("Bob Smith", professors)
+	if professor.Id != 1 || confidence >= instructorMatchThreshold {
+		t.Errorf("closestProfessor = %d, %.2f, want professor 1 below the match threshold", professor.Id, confidence)
+	}
+
+	if professor, confidence := closestProfessor("Bob Smith", nil); professor.Id != 0 || confidence != 0 {
+		t.Errorf("closestProfessor without professors = %d, %.2f", professor.Id, confidence)
+	}
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"strings"
+)
+
+// AvailabilityStatus is whether a section can still be enrolled in, derived from its seat and waitlist counts
+type AvailabilityStatus int
+
+const (
+	AvailabilityUnknown AvailabilityStatus = iota
+	AvailabilityOpen
+	AvailabilityWaitlisted
+	AvailabilityClosed
+)
+
+// Names used by the API (JSON) and by the raw availability strings in the schedule data
+var availabilityStatusNames = map[AvailabilityStatus]string{
+	AvailabilityUnknown:    "unknown",
+	AvailabilityOpen:       "open",
+	AvailabilityWaitlisted: "waitlisted",
+	AvailabilityClosed:     "closed",
+}
+
+func (status AvailabilityStatus) String() string {
+	if name, ok := availabilityStatusNames[status]; ok {
+		return name
+	}
+
+	return availabilityStatusNames[AvailabilityUnknown]
+}
+
+func (status AvailabilityStatus) MarshalText() ([]byte, error) {
+	return []byte(status.String()), nil
+}
+
+func (status *AvailabilityStatus) UnmarshalText(text []byte) error {
+	for s, name := range availabilityStatusNames {
+		if strings.EqualFold(name, strings.TrimSpace(string(text))) {
+			*status = s
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown availability %q", string(text))
+}
+
+func (status AvailabilityStatus) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	text, _ := status.MarshalText()
+	return marshalTextBSONValue(text)
+}
+
+func (status *AvailabilityStatus) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	return unmarshalTextBSONValue(t, data, status.UnmarshalText, func(number int64) {
+		*status = AvailabilityStatus(number)
+	})
+}
+
+// Works out the availability of a section from its counts
+// Falls back to the raw availability string when the schedule data has no seat counts
+func deriveAvailability(availability string, capacity int, enrolled int, waitlistCapacity int, waitlisted int) AvailabilityStatus {
+	if capacity <= 0 {
+		var status AvailabilityStatus
+		if status.UnmarshalText([]byte(availability)) != nil {
+			return AvailabilityUnknown
+		}
+		return status
+	}
+
+	if enrolled < capacity {
+		return AvailabilityOpen
+	}
+
+	if waitlisted < waitlistCapacity {
+		return AvailabilityWaitlisted
+	}
+
+	return AvailabilityClosed
+}
+
+// AvailabilityStatus returns the availability of the class derived from its seat and waitlist counts
+func (class Class) AvailabilityStatus() AvailabilityStatus {
+	return deriveAvailability(class.Availability, class.Capacity, class.Enrolled, class.WaitlistCapacity, class.Waitlisted)
+}
+
+// Returns how many seats are left, 0 when full or unknown
+func openSeats(capacity int, enrolled int) int {
+	if enrolled >= capacity {
+		return 0
+	}
+
+	return capacity - enrolled
+}
+
+// OpenSeats returns how many seats are left in the class, 0 when full or unknown
+func (class Class) OpenSeats() int {
+	return openSeats(class.Capacity, class.Enrolled)
+}
+
+// OpenSeats returns how many seats are left in the class, 0 when full or unknown
+func (class ClassEnhanced) OpenSeats() int {
+	return openSeats(class.Capacity, class.Enrolled)
+}
+
+// Checks the availability, waitlist and open seat constraints against a class
+func fitsAvailabilityConstraints(class Class, constraints UserScheduleConstraints) bool {
+	status := class.AvailabilityStatus()
+
+	found := false
+	for _, preferredAvailability := range constraints.Availability {
+		if preferredAvailability == status {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return false
+	}
+
+	if constraints.MaxWaitlisted != nil && class.Waitlisted > *constraints.MaxWaitlisted {
+		return false
+	}
+
+	// Only applies to classes we have seat counts for
+	if class.Capacity > 0 && class.OpenSeats() < constraints.MinOpenSeats {
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestDeriveAvailability(t *testing.T) {
+	tests := []struct {
+		name             string
+		availability     string
+		capacity         int
+		enrolled         int
+		waitlistCapacity int
+		waitlisted       int
+		want             AvailabilityStatus
+	}{
+		{"seats left", "closed", 30, 29, 5, 0, AvailabilityOpen},
+		{"full with waitlist room", "open", 30, 30, 5, 4, AvailabilityWaitlisted},
+		{"full and waitlist full", "open", 30, 30, 5, 5, AvailabilityClosed},
+		{"over enrolled without waitlist", "", 30, 32, 0, 0, AvailabilityClosed},
+		{"no counts, raw open", "Open", 0, 0, 0, 0, AvailabilityOpen},
+		{"no counts, raw waitlisted", " waitlisted ", 0, 0, 0, 0, AvailabilityWaitlisted},
+		{"no counts, raw closed", "CLOSED", 0, 0, 0, 0, AvailabilityClosed},
+		{"no counts, raw unknown word", "full", 0, 0, 0, 0, AvailabilityUnknown},
+		{"no counts, no raw availability", "", 0, 0, 0, 0, AvailabilityUnknown},
+	}
+
+	for _, test := range tests {
+		got := deriveAvailability(test.availability, test.capacity, test.enrolled, test.waitlistCapacity, test.waitlisted)
+		if got != test.want {
+			t.Errorf("%s: deriveAvailability = %s, want %s", test.name, got, test.want)
+		}
+	}
+}
+
+func TestOpenSeats(t *testing.T) {
+	tests := []struct {
+		capacity int
+		enrolled int
+		want     int
+	}{
+		{30, 10, 20},
+		{30, 30, 0},
+		{30, 35, 0},
+		{0, 0, 0},
+	}
+
+	for _, test := range tests {
+		class := Class{Capacity: test.capacity, Enrolled: test.enrolled}
+		if got := class.OpenSeats(); got != test.want {
+			t.Errorf("Class{Capacity: %d, Enrolled: %d}.OpenSeats() = %d, want %d", test.capacity, test.enrolled, got, test.want)
+		}
+		if got := enhanceClass(class, -1).OpenSeats(); got != test.want {
+			t.Errorf("ClassEnhanced{Capacity: %d, Enrolled: %d}.OpenSeats() = %d, want %d", test.capacity, test.enrolled, got, test.want)
+		}
+	}
+}
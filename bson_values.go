@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+)
+
+// Stores a value by its text form (e.g. "open" or "online"), the same as in JSON
+func marshalTextBSONValue(text []byte) (bsontype.Type, []byte, error) {
+	return bsontype.String, bsoncore.AppendString(nil, string(text)), nil
+}
+
+// Reads a value stored by its text form, or by its number as documents saved before the text form were
+func unmarshalTextBSONValue(t bsontype.Type, data []byte, fromText func([]byte) error, fromNumber func(int64)) error {
+	ok := true
+
+	switch t {
+	case bsontype.String:
+		var value string
+		if value, _, ok = bsoncore.ReadString(data); ok {
+			return fromText([]byte(value))
+		}
+	case bsontype.Int32:
+		var value int32
+		if value, _, ok = bsoncore.ReadInt32(data); ok {
+			fromNumber(int64(value))
+		}
+	case bsontype.Int64:
+		var value int64
+		if value, _, ok = bsoncore.ReadInt64(data); ok {
+			fromNumber(value)
+		}
+	default:
+		return fmt.Errorf("invalid value of type %s", t)
+	}
+
+	if !ok {
+		return fmt.Errorf("invalid value of type %s", t)
+	}
+
+	return nil
+}
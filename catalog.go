@@ -0,0 +1,186 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// CourseSearch is a query over the courses in a school's class data, every field is optional
+type CourseSearch struct {
+	SchoolId             string                `json:"schoolId"`
+	Prefix               string                `json:"prefix"`               // course code starts with, e.g. "MATH 0"
+	Name                 string                `json:"name"`                 // course code contains, case insensitive
+	Instructor           string                `json:"instructor"`           // a section is taught by this instructor (full or partial name)
+	InstructionalMethods []InstructionalMethod `json:"instructionalMethods"` // a section is taught with one of these methods
+	Days                 []string              `json:"days"`                 // a section meets on one of these days ("monday" ... "sunday")
+	Limit                int                   `json:"limit"`                // maximum number of results, 0 for no limit
+}
+
+// CourseSearchResult is a course with at least one section matching a CourseSearch
+type CourseSearchResult struct {
+	CourseName           string                `json:"courseName"`
+	Sections             int                   `json:"sections"`     // number of matching sections
+	OpenSections         int                   `json:"openSections"` // number of matching sections that are open
+	Instructors          []string              `json:"instructors"`
+	InstructionalMethods []InstructionalMethod `json:"instructionalMethods"`
+}
+
+// Upper case letters and digits of a course code, keeping zero padding ("math 00" -> "MATH00")
+func compactCourseCode(code string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToUpper(r)
+		}
+		return -1
+	}, code)
+}
+
+// Returns true if the meeting time is on the given day
+func meetsOnDay(meetingTime MeetingTime, day string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(day)) {
+	case "monday":
+		return meetingTime.Monday, nil
+	case "tuesday":
+		return meetingTime.Tuesday, nil
+	case "wednesday":
+		return meetingTime.Wednesday, nil
+	case "thursday":
+		return meetingTime.Thursday, nil
+	case "friday":
+		return meetingTime.Friday, nil
+	case "saturday":
+		return meetingTime.Saturday, nil
+	case "sunday":
+		return meetingTime.Sunday, nil
+	default:
+		return false, fmt.Errorf("unknown day %q", day)
+	}
+}
+
+// Checks a single section against the search, whose days must already be valid (see searchCourses)
+func sectionMatchesSearch(class Class, search CourseSearch) bool {
+	if search.Prefix != "" {
+		// Either exactly as typed, or ignoring zero padding ("MATH 8" finds "MATH 008")
+		if !strings.HasPrefix(compactCourseCode(class.CourseName), compactCourseCode(search.Prefix)) &&
+			!strings.HasPrefix(normalizeCourseCode(class.CourseName), normalizeCourseCode(search.Prefix)) {
+			return false
+		}
+	}
+
+	if search.Name != "" && !strings.Contains(strings.ToUpper(class.CourseName), strings.ToUpper(strings.TrimSpace(search.Name))) {
+		return false
+	}
+
+	if search.Instructor != "" {
+		// Partial names are enough for autocomplete, full names are matched like everywhere else
+		if !strings.Contains(normalizeName(class.Instructor), normalizeName(search.Instructor)) &&
+			!instructorNamesMatch(class.Instructor, search.Instructor) {
+			return false
+		}
+	}
+
+	if len(search.InstructionalMethods) > 0 {
+		found := false
+		for _, method := range search.InstructionalMethods {
+			if method == class.Method {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if len(search.Days) > 0 {
+		found := false
+		for _, meetingTime := range class.MeetingTimes {
+			for _, day := range search.Days {
+				if meets, _ := meetsOnDay(meetingTime, day); meets {
+					found = true
+				}
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Searches the courses of a school document, returning each course with matching sections once, sorted by course name
+// The school's instructional methods must already be resolved (see normalizeInstructionalMethods)
+func searchCourses(school School, search CourseSearch) ([]CourseSearchResult, error) {
+	// Unknown days are reported even when no section has meeting times to check them against
+	for _, day := range search.Days {
+		if _, err := meetsOnDay(MeetingTime{}, day); err != nil {
+			return nil, err
+		}
+	}
+
+	results := []CourseSearchResult{}
+	positions := map[string]int{}
+
+	for _, class := range school.Classes {
+		if !sectionMatchesSearch(class, search) {
+			continue
+		}
+
+		i, ok := positions[class.CourseName]
+		if !ok {
+			i = len(results)
+			positions[class.CourseName] = i
+			results = append(results, CourseSearchResult{CourseName: class.CourseName, Instructors: []string{}, InstructionalMethods: []InstructionalMethod{}})
+		}
+
+		result := &results[i]
+		result.Sections++
+
+		if class.AvailabilityStatus() == AvailabilityOpen {
+			result.OpenSections++
+		}
+
+		if class.Instructor != "" && !stringInList(class.Instructor, result.Instructors) {
+			result.Instructors = append(result.Instructors, class.Instructor)
+		}
+
+		methodFound := false
+		for _, method := range result.InstructionalMethods {
+			if method == class.Method {
+				methodFound = true
+				break
+			}
+		}
+		if !methodFound {
+			result.InstructionalMethods = append(result.InstructionalMethods, class.Method)
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].CourseName < results[j].CourseName
+	})
+
+	for _, result := range results {
+		sort.Strings(result.Instructors)
+	}
+
+	if search.Limit > 0 && len(results) > search.Limit {
+		results = results[:search.Limit]
+	}
+
+	return results, nil
+}
+
+// Returns true if the string is in the list
+func stringInList(s string, list []string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+
+	return false
+}
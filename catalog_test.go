@@ -0,0 +1,79 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func catalogTestSchool() School {
+	return School{SchoolId: "pcc", Classes: []Class{
+		{ClassID: "100", CourseName: "MATH 008", Instructor: "John Smith", Method: MethodInPerson, Capacity: 30, Enrolled: 10,
+			MeetingTimes: []MeetingTime{testMeeting("MW", Time{8, 0}, Time{9, 0})}},
+		{ClassID: "101", CourseName: "MATH 008", Instructor: "Ann Lee", Method: MethodOnline, Capacity: 30, Enrolled: 30},
+		{ClassID: "200", CourseName: "MATH 005A", Instructor: "John Smith", Method: MethodHybrid, Capacity: 30, Enrolled: 5,
+			MeetingTimes: []MeetingTime{testMeeting("T", Time{10, 0}, Time{11, 0})}},
+		{ClassID: "300", CourseName: "ENGL 001A", Instructor: "Jane Doe", Method: MethodInPerson,
+			MeetingTimes: []MeetingTime{testMeeting("F", Time{10, 0}, Time{11, 0})}},
+	}}
+}
+
+func TestSearchCourses(t *testing.T) {
+	tests := []struct {
+		name   string
+		search CourseSearch
+		want   []string // course names, in order
+	}{
+		{"everything, sorted", CourseSearch{}, []string{"ENGL 001A", "MATH 005A", "MATH 008"}},
+		{"prefix with zero padding", CourseSearch{Prefix: "math 0"}, []string{"MATH 005A", "MATH 008"}},
+		{"prefix without zero padding", CourseSearch{Prefix: "MATH 8"}, []string{"MATH 008"}},
+		{"name", CourseSearch{Name: "engl"}, []string{"ENGL 001A"}},
+		{"partial instructor", CourseSearch{Instructor: "smi"}, []string{"MATH 005A", "MATH 008"}},
+		{"instructor name order", CourseSearch{Instructor: "Doe, Jane"}, []string{"ENGL 001A"}},
+		{"method", CourseSearch{InstructionalMethods: []InstructionalMethod{MethodOnline}}, []string{"MATH 008"}},
+		{"days", CourseSearch{Days: []string{"Tuesday", "friday"}}, []string{"ENGL 001A", "MATH 005A"}},
+		{"limit", CourseSearch{Limit: 2}, []string{"ENGL 001A", "MATH 005A"}},
+		{"nothing matches", CourseSearch{Name: "CHEM"}, []string{}},
+	}
+
+	for _, test := range tests {
+		results, err := searchCourses(catalogTestSchool(), test.search)
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+
+		got := []string{}
+		for _, result := range results {
+			got = append(got, result.CourseName)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: courses = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestSearchCoursesResult(t *testing.T) {
+	results, err := searchCourses(catalogTestSchool(), CourseSearch{Prefix: "MATH 008"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []CourseSearchResult{{
+		CourseName:           "MATH 008",
+		Sections:             2,
+		OpenSections:         1,
+		Instructors:          []string{"Ann Lee", "John Smith"},
+		InstructionalMethods: []InstructionalMethod{MethodInPerson, MethodOnline},
+	}}
+	if !reflect.DeepEqual(results, want) {
+		t.Errorf("results = %+v, want %+v", results, want)
+	}
+}
+
+func TestSearchCoursesUnknownDay(t *testing.T) {
+	for _, school := range []School{catalogTestSchool(), {}} {
+		if _, err := searchCourses(school, CourseSearch{Days: []string{"someday"}}); err == nil {
+			t.Errorf("searching %d sections for an unknown day returned no error", len(school.Classes))
+		}
+	}
+}
@@ -0,0 +1,940 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Runs one of the maintenance commands given on the command line
+func runCommand(name string, args []string) error {
+	switch name {
+	case "unmatched":
+		return runUnmatchedCommand(args)
+	case "alias":
+		return runAliasCommand(args)
+	case "search":
+		return runSearchCommand(args)
+	case "import-classes":
+		return runImportClassesCommand(args)
+	case "import-professors":
+		return runImportProfessorsCommand(args)
+	case "diff":
+		return runDiffCommand(args)
+	case "revalidate":
+		return runRevalidateCommand(args)
+	case "profile":
+		return runProfileCommand(args)
+	case "favorite":
+		return runFavoriteCommand(args)
+	case "export-ics":
+		return runExportICSCommand(args)
+	case "render":
+		return runRenderCommand(args)
+	case "generate":
+		return runGenerateCommand(args)
+	default:
+		return fmt.Errorf("unknown command %q (expected unmatched, alias, search, import-classes, import-professors, diff, revalidate, profile, favorite, export-ics, render or generate)", name)
+	}
+}
+
+// Lists the instructors in the latest class data that match no professor, or only with low confidence,
+// so staff know which instructor aliases to add
+func runUnmatchedCommand(args []string) error {
+	flags := flag.NewFlagSet("unmatched", flag.ExitOnError)
+	schoolId := flags.String("school", "", "school id (e.g. pcc)")
+	threshold := flags.Float64("threshold", 0.95, "matches below this confidence are listed as low confidence")
+	flags.Parse(args)
+
+	school, err := fetchClassData(*schoolId)
+	if err != nil {
+		return err
+	}
+
+	professorsExport, err := fetchProfessorData(*schoolId)
+	if err != nil {
+		return err
+	}
+
+	aliases, err := fetchInstructorAliases(*schoolId)
+	if err != nil {
+		return err
+	}
+	aliasIndex := indexInstructorAliases(aliases)
+	index := professorIndexFor(professorsExport)
+
+	// One course per distinct instructor is enough to know their department
+	instructorCourses := map[string]string{}
+	for _, class := range school.Classes {
+		if _, ok := instructorCourses[class.Instructor]; !ok {
+			instructorCourses[class.Instructor] = class.CourseName
+		}
+	}
+
+	instructors := []string{}
+	for instructor := range instructorCourses {
+		instructors = append(instructors, instructor)
+	}
+	sort.Strings(instructors)
+
+	for _, instructor := range instructors {
+		if _, ok := aliasedProfessor(instructor, aliasIndex, index); ok {
+			continue
+		}
+
+		professor, confidence, found := index.match(*schoolId, instructorCourses[instructor], instructor)
+		if found && confidence >= *threshold {
+			continue
+		}
+
+		if !found {
+			// Show the closest professor anyway, it is often the right one
+			professor, confidence = closestProfessor(instructor, professorsExport.Professors)
+			fmt.Printf("UNMATCHED  %-30s closest: %s (id %d, %.2f)\n", instructor, professorFullName(professor), professor.Id, confidence)
+			continue
+		}
+
+		fmt.Printf("LOW        %-30s matched: %s (id %d, %.2f)\n", instructor, professorFullName(professor), professor.Id, confidence)
+	}
+
+	return nil
+}
+
+// Lists, adds or removes instructor aliases
+func runAliasCommand(args []string) error {
+	if len(args) == 0 {
+		return errors.New("expected alias list, alias add or alias remove")
+	}
+
+	flags := flag.NewFlagSet("alias "+args[0], flag.ExitOnError)
+	schoolId := flags.String("school", "", "school id (e.g. pcc)")
+	instructor := flags.String("instructor", "", "instructor name exactly as it appears in the schedule data")
+	professorId := flags.Int("professor", 0, "rate my professor id of the professor")
+	flags.Parse(args[1:])
+
+	if _, err := lookupSchool(*schoolId); err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "list":
+		aliases, err := fetchInstructorAliases(*schoolId)
+		if err != nil {
+			return err
+		}
+
+		for _, alias := range aliases {
+			fmt.Println(alias.Instructor + " -> " + strconv.Itoa(alias.ProfessorId))
+		}
+		return nil
+	case "add":
+		if *instructor == "" || *professorId == 0 {
+			return errors.New("alias add needs -instructor and -professor")
+		}
+		return saveInstructorAlias(InstructorAlias{SchoolId: *schoolId, Instructor: *instructor, ProfessorId: *professorId})
+	case "remove":
+		if *instructor == "" {
+			return errors.New("alias remove needs -instructor")
+		}
+		return deleteInstructorAlias(*schoolId, *instructor)
+	default:
+		return fmt.Errorf("unknown alias command %q", args[0])
+	}
+}
+
+// Searches the courses in the latest class data of a school
+func runSearchCommand(args []string) error {
+	flags := flag.NewFlagSet("search", flag.ExitOnError)
+	search := CourseSearch{}
+	flags.StringVar(&search.SchoolId, "school", "", "school id (e.g. pcc)")
+	flags.StringVar(&search.Prefix, "prefix", "", "course code starts with")
+	flags.StringVar(&search.Name, "name", "", "course code contains")
+	flags.StringVar(&search.Instructor, "instructor", "", "taught by instructor")
+	methods := flags.String("methods", "", "comma separated instructional methods (inPerson, hybrid, online)")
+	days := flags.String("days", "", "comma separated days the course meets on (monday ... sunday)")
+	flags.IntVar(&search.Limit, "limit", 0, "maximum number of courses")
+	asJSON := flags.Bool("json", false, "print the results as JSON")
+	flags.Parse(args)
+
+	if *methods != "" {
+		for _, name := range strings.Split(*methods, ",") {
+			var method InstructionalMethod
+			if err := method.UnmarshalText([]byte(strings.TrimSpace(name))); err != nil {
+				return err
+			}
+			search.InstructionalMethods = append(search.InstructionalMethods, method)
+		}
+	}
+
+	if *days != "" {
+		search.Days = strings.Split(*days, ",")
+	}
+
+	school, err := fetchClassData(search.SchoolId)
+	if err != nil {
+		return err
+	}
+	warnUnknownInstructionalMethods(normalizeInstructionalMethods(&school, search.SchoolId))
+
+	results, err := searchCourses(school, search)
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		output, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(output))
+		return nil
+	}
+
+	for _, result := range results {
+		fmt.Printf("%-12s %3d sections (%d open)  %s\n", result.CourseName, result.Sections, result.OpenSections, strings.Join(result.Instructors, "; "))
+	}
+
+	return nil
+}
+
+// Validates a JSON or CSV export of sections and saves it as the newest class data of a school
+func runImportClassesCommand(args []string) error {
+	flags := flag.NewFlagSet("import-classes", flag.ExitOnError)
+	schoolId := flags.String("school", "", "school id (e.g. pcc)")
+	file := flags.String("file", "", "JSON or CSV export of the sections")
+	format := flags.String("format", "", "json or csv (default: from the file extension)")
+	dryRun := flags.Bool("dry-run", false, "validate without saving")
+	skipInvalid := flags.Bool("skip-invalid", false, "save the valid sections even if some rows have errors")
+	flags.Parse(args)
+
+	if *file == "" {
+		return errors.New("import-classes needs -file")
+	}
+
+	if _, err := lookupSchool(*schoolId); err != nil {
+		return err
+	}
+
+	if *format == "" {
+		*format = strings.TrimPrefix(strings.ToLower(filepath.Ext(*file)), ".")
+	}
+
+	data, err := os.ReadFile(*file)
+	if err != nil {
+		return err
+	}
+
+	var imported []importedClass
+	importErrors := []ImportError{}
+
+	switch *format {
+	case "json":
+		imported, err = parseClassesJSON(data)
+	case "csv":
+		imported, importErrors, err = parseClassesCSV(strings.NewReader(string(data)))
+	default:
+		return fmt.Errorf("unknown format %q (expected json or csv)", *format)
+	}
+	if err != nil {
+		return err
+	}
+
+	classes, validationErrors := validateClasses(*schoolId, imported)
+	importErrors = append(importErrors, validationErrors...)
+
+	for _, importError := range importErrors {
+		fmt.Println(importError.Error())
+	}
+	fmt.Printf("%d valid sections, %d errors\n", len(classes), len(importErrors))
+
+	if len(importErrors) > 0 && !*skipInvalid {
+		return errors.New("not saving class data with errors (use -skip-invalid to save the valid sections)")
+	}
+
+	if *dryRun {
+		return nil
+	}
+
+	school, err := newSchoolDocument(*schoolId, classes)
+	if err != nil {
+		return err
+	}
+
+	if err := saveClassData(school); err != nil {
+		return err
+	}
+
+	fmt.Println("Saved class data " + strconv.FormatInt(school.Timestamp, 10))
+	return nil
+}
+
+// Cleans a JSON export of rate my professor professors and saves it as the newest professor data of a school
+func runImportProfessorsCommand(args []string) error {
+	flags := flag.NewFlagSet("import-professors", flag.ExitOnError)
+	schoolId := flags.String("school", "", "school id (e.g. pcc)")
+	file := flags.String("file", "", "JSON export of the professors")
+	dryRun := flags.Bool("dry-run", false, "validate without saving")
+	flags.Parse(args)
+
+	if *file == "" {
+		return errors.New("import-professors needs -file")
+	}
+
+	if _, err := lookupSchool(*schoolId); err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(*file)
+	if err != nil {
+		return err
+	}
+
+	professors, err := parseProfessorsJSON(data)
+	if err != nil {
+		return err
+	}
+
+	professors, importErrors, warnings := cleanProfessors(professors)
+
+	for _, importError := range importErrors {
+		fmt.Println("Error: " + importError.Error())
+	}
+	for _, warning := range warnings {
+		fmt.Println("Possible duplicate: " + warning.Error())
+	}
+	fmt.Printf("%d professors, %d dropped, %d possible duplicates\n", len(professors), len(importErrors), len(warnings))
+
+	if *dryRun {
+		return nil
+	}
+
+	export, err := newProfessorExport(*schoolId, professors)
+	if err != nil {
+		return err
+	}
+
+	if err := saveProfessorData(export); err != nil {
+		return err
+	}
+
+	fmt.Println("Saved professor data version " + strconv.Itoa(export.Version))
+	return nil
+}
+
+// Compares two class data snapshots of a school, by default the two most recent ones
+func runDiffCommand(args []string) error {
+	flags := flag.NewFlagSet("diff", flag.ExitOnError)
+	schoolId := flags.String("school", "", "school id (e.g. pcc)")
+	fromTimestamp := flags.Int64("from", 0, "timestamp of the older snapshot (default: second most recent)")
+	toTimestamp := flags.Int64("to", 0, "timestamp of the newer snapshot (default: most recent)")
+	asJSON := flags.Bool("json", false, "print the differences as JSON")
+	favorites := flags.Bool("favorites", false, "also list the saved favorite schedules with a removed or changed section")
+	flags.Parse(args)
+
+	var from, to School
+	var err error
+
+	if *fromTimestamp == 0 || *toTimestamp == 0 {
+		recent, err := fetchRecentClassData(*schoolId, 2)
+		if err != nil {
+			return err
+		}
+		if len(recent) < 2 {
+			return errors.New("need at least two class data snapshots to compare")
+		}
+		to, from = recent[0], recent[1]
+	}
+
+	if *fromTimestamp != 0 {
+		if from, err = fetchClassDataSnapshot(*schoolId, *fromTimestamp); err != nil {
+			return err
+		}
+	}
+
+	if *toTimestamp != 0 {
+		if to, err = fetchClassDataSnapshot(*schoolId, *toTimestamp); err != nil {
+			return err
+		}
+	}
+
+	diff := diffClassData(from, to)
+	affected := diff.affectedClassIDs()
+
+	affectedFavorites := []FavoriteSchedule{}
+	if *favorites && len(affected) > 0 {
+		if affectedFavorites, err = fetchFavoriteSchedulesWithClasses(*schoolId, affected); err != nil {
+			return err
+		}
+	}
+
+	if *asJSON {
+		output, err := json.MarshalIndent(struct {
+			ClassDataDiff
+			AffectedClassIDs  []string           `json:"affectedClassIDs"`
+			AffectedFavorites []FavoriteSchedule `json:"affectedFavorites,omitempty"`
+		}{diff, affected, affectedFavorites}, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(output))
+		return nil
+	}
+
+	fmt.Printf("Changes from %d to %d\n", diff.FromTimestamp, diff.ToTimestamp)
+	for _, class := range diff.Added {
+		fmt.Printf("ADDED      %-10s %-12s %s\n", class.ClassID, class.CourseName, formatClassTimes(class))
+	}
+	for _, class := range diff.Removed {
+		fmt.Printf("REMOVED    %-10s %-12s %s\n", class.ClassID, class.CourseName, formatClassTimes(class))
+	}
+	for _, change := range diff.Changes {
+		fmt.Printf("CHANGED    %-10s %-12s %s: %s -> %s\n", change.ClassID, change.CourseName, change.Kind, change.Before, change.After)
+	}
+
+	if len(affected) > 0 {
+		fmt.Printf("Removed or changed sections: %s\n", strings.Join(affected, ", "))
+	}
+
+	if *favorites {
+		fmt.Printf("Favorite schedules affected: %d\n", len(affectedFavorites))
+		for _, favorite := range affectedFavorites {
+			classIDs := []string{}
+			for _, classID := range favorite.ClassIDs {
+				if classIDInList(classID, affected) {
+					classIDs = append(classIDs, classID)
+				}
+			}
+			fmt.Printf("  %s %q v%d: %s\n", favorite.UserId, favorite.Name, favorite.Version, strings.Join(classIDs, ", "))
+		}
+	}
+
+	return nil
+}
+
+// Checks a saved schedule against the latest class data and suggests replacements for the sections that no longer work
+func runRevalidateCommand(args []string) error {
+	flags := flag.NewFlagSet("revalidate", flag.ExitOnError)
+	constraintsFile := flags.String("constraints", "", "JSON file with the schedule constraints the schedule was made with")
+	classIDs := flags.String("classes", "", "comma separated class ids of the schedule")
+	since := flags.Int64("since", 0, "timestamp of the class data the schedule was made with, to report moved sections and new instructors")
+	asJSON := flags.Bool("json", false, "print the result as JSON")
+	flags.Parse(args)
+
+	if *constraintsFile == "" || *classIDs == "" {
+		return errors.New("-constraints and -classes are required")
+	}
+
+	constraints, err := readConstraintsFile(*constraintsFile)
+	if err != nil {
+		return err
+	}
+
+	ids := []string{}
+	for _, classID := range strings.Split(*classIDs, ",") {
+		ids = append(ids, strings.TrimSpace(classID))
+	}
+
+	revalidation, err := revalidateSavedSchedule(ids, constraints, *since)
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		output, err := json.MarshalIndent(revalidation, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(output))
+		return nil
+	}
+
+	printScheduleRevalidation(revalidation)
+
+	return nil
+}
+
+// Prints the issues and replacement suggestions of a schedule revalidation
+func printScheduleRevalidation(revalidation ScheduleRevalidation) {
+	if revalidation.Valid {
+		fmt.Printf("Schedule is still valid with the class data of %d\n", revalidation.Timestamp)
+	} else {
+		fmt.Printf("Schedule needs changes with the class data of %d\n", revalidation.Timestamp)
+	}
+
+	for _, issue := range revalidation.Issues {
+		fmt.Printf("%-18s %-10s %-12s %s\n", strings.ToUpper(string(issue.Kind)), issue.ClassID, issue.CourseName, issue.Message)
+	}
+
+	for _, suggestion := range revalidation.Replacements {
+		if len(suggestion.Replacements) == 0 {
+			fmt.Printf("No replacement found for %s (%s)\n", suggestion.ClassID, suggestion.CourseName)
+			continue
+		}
+
+		fmt.Printf("Replacements for %s (%s):\n", suggestion.ClassID, suggestion.CourseName)
+		for _, class := range suggestion.Replacements {
+			fmt.Printf("    %-10s %-12s %-25s %s\n", class.ClassID, class.CourseName, class.Instructor, formatClassTimes(class))
+		}
+	}
+}
+
+// Lists, shows, saves or removes the constraint profiles of a user
+func runProfileCommand(args []string) error {
+	if len(args) == 0 {
+		return errors.New("expected profile list, profile show, profile save or profile remove")
+	}
+
+	flags := flag.NewFlagSet("profile "+args[0], flag.ExitOnError)
+	userId := flags.String("user", "", "user id")
+	name := flags.String("name", "", "profile name")
+	file := flags.String("file", "", "JSON file with the schedule constraints to save")
+	flags.Parse(args[1:])
+
+	if *userId == "" {
+		return errors.New("-user is required")
+	}
+
+	switch args[0] {
+	case "list":
+		profiles, err := fetchConstraintProfiles(*userId)
+		if err != nil {
+			return err
+		}
+
+		for _, profile := range profiles {
+			fmt.Printf("%-20s %-6s %s\n", profile.Name, profile.Constraints.SchoolId, strings.Join(profile.Constraints.Courses, ", "))
+		}
+		return nil
+	case "show":
+		profile, err := fetchConstraintProfile(*userId, *name)
+		if err != nil {
+			return err
+		}
+
+		output, err := json.MarshalIndent(profile, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(output))
+		return nil
+	case "save":
+		if *name == "" || *file == "" {
+			return errors.New("profile save needs -name and -file")
+		}
+
+		constraints, err := readConstraintsFile(*file)
+		if err != nil {
+			return err
+		}
+
+		return saveConstraintProfile(ConstraintProfile{UserId: *userId, Name: *name, Constraints: constraints})
+	case "remove":
+		if *name == "" {
+			return errors.New("profile remove needs -name")
+		}
+		return deleteConstraintProfile(*userId, *name)
+	default:
+		return fmt.Errorf("unknown profile command %q", args[0])
+	}
+}
+
+// Lists, saves, removes, compares or re-checks the favorite schedules of a user
+func runFavoriteCommand(args []string) error {
+	if len(args) == 0 {
+		return errors.New("expected favorite list, favorite save, favorite remove, favorite compare or favorite check")
+	}
+
+	flags := flag.NewFlagSet("favorite "+args[0], flag.ExitOnError)
+	userId := flags.String("user", "", "user id")
+	name := flags.String("name", "", "favorite name")
+	version := flags.Int("version", 0, "favorite version (default: latest, or every version for remove)")
+	fromVersion := flags.Int("from", 0, "older version to compare")
+	toVersion := flags.Int("to", 0, "newer version to compare (default: latest)")
+	profileName := flags.String("profile", "", "constraint profile the schedule was generated with")
+	classIDs := flags.String("classes", "", "comma separated class ids of the schedule")
+	timestamp := flags.Int64("timestamp", 0, "timestamp of the class data the schedule was generated from (default: latest)")
+	flags.Parse(args[1:])
+
+	if *userId == "" {
+		return errors.New("-user is required")
+	}
+
+	switch args[0] {
+	case "list":
+		favorites, err := fetchFavoriteSchedules(*userId)
+		if err != nil {
+			return err
+		}
+
+		for _, favorite := range favorites {
+			fmt.Printf("%-20s v%-3d %8.2f  %s\n", favorite.Name, favorite.Version, favorite.Score, strings.Join(favorite.ClassIDs, ", "))
+		}
+		return nil
+	case "save":
+		if *name == "" || *profileName == "" || *classIDs == "" {
+			return errors.New("favorite save needs -name, -profile and -classes")
+		}
+
+		profile, err := fetchConstraintProfile(*userId, *profileName)
+		if err != nil {
+			return err
+		}
+
+		var school School
+		if *timestamp == 0 {
+			school, err = fetchClassData(profile.Constraints.SchoolId)
+		} else {
+			school, err = fetchClassDataSnapshot(profile.Constraints.SchoolId, *timestamp)
+		}
+		if err != nil {
+			return err
+		}
+		warnUnknownInstructionalMethods(normalizeInstructionalMethods(&school, profile.Constraints.SchoolId))
+
+		professorsExport, err := fetchProfessorData(profile.Constraints.SchoolId)
+		if err != nil {
+			return err
+		}
+
+		aliases, err := fetchInstructorAliases(profile.Constraints.SchoolId)
+		if err != nil {
+			return err
+		}
+
+		ids := []string{}
+		for _, classID := range strings.Split(*classIDs, ",") {
+			ids = append(ids, strings.TrimSpace(classID))
+		}
+
+		score, err := scoreSavedSchedule(school, ids, professorsExport, aliases, profile.Constraints)
+		if err != nil {
+			return err
+		}
+
+		favorite, err := saveFavoriteSchedule(FavoriteSchedule{
+			UserId:             *userId,
+			Name:               *name,
+			ClassIDs:           ids,
+			Score:              score,
+			ClassDataTimestamp: school.Timestamp,
+			Constraints:        profile.Constraints,
+		})
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Saved %s version %d, score %.2f\n", favorite.Name, favorite.Version, favorite.Score)
+		return nil
+	case "remove":
+		if *name == "" {
+			return errors.New("favorite remove needs -name")
+		}
+		return deleteFavoriteSchedule(*userId, *name, *version)
+	case "compare":
+		if *name == "" || *fromVersion == 0 {
+			return errors.New("favorite compare needs -name and -from")
+		}
+
+		from, err := fetchFavoriteSchedule(*userId, *name, *fromVersion)
+		if err != nil {
+			return err
+		}
+		to, err := fetchFavoriteSchedule(*userId, *name, *toVersion)
+		if err != nil {
+			return err
+		}
+
+		comparison := compareFavoriteSchedules(from, to)
+		fmt.Printf("Version %d -> %d, score %+.2f\n", comparison.FromVersion, comparison.ToVersion, comparison.ScoreChange)
+		if comparison.ClassDataChanged {
+			fmt.Printf("Generated from different class data (%d -> %d)\n", from.ClassDataTimestamp, to.ClassDataTimestamp)
+		}
+		for _, classID := range comparison.Added {
+			fmt.Println("+ " + classID)
+		}
+		for _, classID := range comparison.Removed {
+			fmt.Println("- " + classID)
+		}
+		return nil
+	case "check":
+		if *name == "" {
+			return errors.New("favorite check needs -name")
+		}
+
+		favorite, err := fetchFavoriteSchedule(*userId, *name, *version)
+		if err != nil {
+			return err
+		}
+
+		revalidation, err := revalidateSavedSchedule(favorite.ClassIDs, favorite.Constraints, favorite.ClassDataTimestamp)
+		if err != nil {
+			return err
+		}
+
+		printScheduleRevalidation(revalidation)
+		return nil
+	default:
+		return fmt.Errorf("unknown favorite command %q", args[0])
+	}
+}
+
+// Loads the schedule named by the flags of a schedule export, either a favorite schedule or a list of class ids
+func loadScheduleToExport(userId string, name string, version int, schoolId string, classIDs string) ([]ClassEnhanced, string, error) {
+	ids := []string{}
+
+	if name != "" {
+		favorite, err := fetchFavoriteSchedule(userId, name, version)
+		if err != nil {
+			return nil, "", err
+		}
+		schoolId = favorite.Constraints.SchoolId
+		ids = favorite.ClassIDs
+	} else {
+		if schoolId == "" || classIDs == "" {
+			return nil, "", errors.New("either -user and -name, or -school and -classes are required")
+		}
+		for _, classID := range strings.Split(classIDs, ",") {
+			ids = append(ids, strings.TrimSpace(classID))
+		}
+	}
+
+	school, err := fetchClassData(schoolId)
+	if err != nil {
+		return nil, "", err
+	}
+	warnUnknownInstructionalMethods(normalizeInstructionalMethods(&school, schoolId))
+
+	classes, err := savedScheduleClasses(school, ids)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return classes, schoolId, nil
+}
+
+// Writes a schedule as an iCalendar file to import into Google or Apple Calendar
+func runExportICSCommand(args []string) error {
+	flags := flag.NewFlagSet("export-ics", flag.ExitOnError)
+	userId := flags.String("user", "", "user id of the favorite schedule")
+	name := flags.String("name", "", "name of the favorite schedule")
+	version := flags.Int("version", 0, "version of the favorite schedule (default: latest)")
+	schoolId := flags.String("school", "", "school id (e.g. pcc), when exporting -classes")
+	classIDs := flags.String("classes", "", "comma separated class ids of the schedule")
+	year := flags.Int("year", 0, "year the sections start in (default: the year of the school's term calendar covering the sections, required if it has none)")
+	out := flags.String("out", "", "file to write (default: standard output)")
+	flags.Parse(args)
+
+	classes, scheduleSchoolId, err := loadScheduleToExport(*userId, *name, *version, *schoolId, *classIDs)
+	if err != nil {
+		return err
+	}
+
+	calendar, skipped, err := exportICalendar(classes, scheduleSchoolId, *year)
+	if err != nil {
+		return err
+	}
+
+	if len(skipped) > 0 {
+		fmt.Fprintln(os.Stderr, "Not on the calendar (no meeting times or dates): "+strings.Join(skipped, ", "))
+	}
+
+	if *out == "" {
+		fmt.Print(calendar)
+		return nil
+	}
+
+	return os.WriteFile(*out, []byte(calendar), 0644)
+}
+
+// Draws a schedule as a weekly grid, as a terminal table, an HTML page or an SVG image
+func runRenderCommand(args []string) error {
+	flags := flag.NewFlagSet("render", flag.ExitOnError)
+	userId := flags.String("user", "", "user id of the favorite schedule")
+	name := flags.String("name", "", "name of the favorite schedule")
+	version := flags.Int("version", 0, "version of the favorite schedule (default: latest)")
+	schoolId := flags.String("school", "", "school id (e.g. pcc), when rendering -classes")
+	classIDs := flags.String("classes", "", "comma separated class ids of the schedule")
+	format := flags.String("format", "text", "text, html or svg")
+	color := flags.Bool("color", false, "color the text grid for the terminal")
+	out := flags.String("out", "", "file to write (default: standard output)")
+	flags.Parse(args)
+
+	classes, _, err := loadScheduleToExport(*userId, *name, *version, *schoolId, *classIDs)
+	if err != nil {
+		return err
+	}
+
+	title := "Schedule"
+	if *name != "" {
+		title = *name
+	}
+
+	var rendered string
+	switch *format {
+	case "text":
+		rendered = renderScheduleText(classes, *color)
+	case "html":
+		rendered = renderScheduleHTML(classes, title)
+	case "svg":
+		rendered = renderScheduleSVG(classes)
+	default:
+		return fmt.Errorf("unknown format %q (expected text, html or svg)", *format)
+	}
+
+	if *out == "" {
+		fmt.Print(rendered)
+		return nil
+	}
+
+	return os.WriteFile(*out, []byte(rendered), 0644)
+}
+
+// Reads schedule constraints from a JSON file
+func readConstraintsFile(file string) (UserScheduleConstraints, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return UserScheduleConstraints{}, err
+	}
+
+	var constraints UserScheduleConstraints
+	if err := json.Unmarshal(data, &constraints); err != nil {
+		return UserScheduleConstraints{}, fmt.Errorf("unable to read constraints: %w", err)
+	}
+
+	return constraints, nil
+}
+
+// Generates the ranked schedules for a set of constraints and prints them or exports them as CSV or JSON
+func runGenerateCommand(args []string) error {
+	flags := flag.NewFlagSet("generate", flag.ExitOnError)
+	constraintsFile := flags.String("constraints", "", "JSON file with the schedule constraints")
+	userId := flags.String("user", "", "user id of the constraint profile")
+	profileName := flags.String("profile", "", "constraint profile to generate with, instead of -constraints")
+	format := flags.String("format", "text", "text, grid, html, svg, csv or json")
+	limit := flags.Int("limit", 10, "number of schedules to output, 0 for all of them (10 with the diverse and mmr selection modes)")
+	rank := flags.Int("rank", 0, "only output the schedule with this rank, in every format but text (default: every schedule, the first one for svg)")
+	color := flags.Bool("color", false, "color the grid for the terminal")
+	out := flags.String("out", "", "file to write (default: standard output)")
+	flags.Parse(args)
+
+	switch *format {
+	case "text", "grid", "html", "svg", "csv", "json":
+	default:
+		return fmt.Errorf("unknown format %q (expected text, grid, html, svg, csv or json)", *format)
+	}
+
+	var constraints UserScheduleConstraints
+	var err error
+
+	switch {
+	case *profileName != "":
+		profile, err := fetchConstraintProfile(*userId, *profileName)
+		if err != nil {
+			return err
+		}
+		constraints = profile.Constraints
+	case *constraintsFile != "":
+		if constraints, err = readConstraintsFile(*constraintsFile); err != nil {
+			return err
+		}
+	default:
+		return errors.New("either -constraints, or -user and -profile are required")
+	}
+
+	school, err := fetchClassData(constraints.SchoolId)
+	if err != nil {
+		return err
+	}
+	warnUnknownInstructionalMethods(normalizeInstructionalMethods(&school, constraints.SchoolId))
+
+	// Kept off the standard output, which may be the schedules themselves
+	reportConstraintProblems(os.Stderr, school, constraints)
+
+	classes, err := prepareClasses(school, constraints)
+	if err != nil {
+		return err
+	}
+
+	schedules := generateSchedule(classes, constraints)
+	selected := breakdownSchedules(selectSchedules(schedules, constraints, *limit), constraints)
+
+	if *format == "text" {
+		fmt.Println("Number of possible schedules: " + strconv.Itoa(len(schedules)))
+		printSchedules(selected)
+		return nil
+	}
+
+	// Ranks of the schedules to output, 1 based
+	ranks := []int{}
+	for i := range selected {
+		ranks = append(ranks, i+1)
+	}
+	if *rank == 0 && *format == "svg" && len(selected) > 0 {
+		*rank = 1
+	}
+	if *rank != 0 {
+		if *rank < 0 || *rank > len(selected) {
+			return fmt.Errorf("-rank %d is out of range, %d schedules were selected", *rank, len(selected))
+		}
+		ranks = []int{*rank}
+	}
+
+	output := os.Stdout
+	if *out != "" {
+		file, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		output = file
+	}
+
+	heading := func(rank int) string {
+		return fmt.Sprintf("Schedule #%d, rating %.2f", rank, selected[rank-1].Rating)
+	}
+
+	switch *format {
+	case "grid":
+		for _, rank := range ranks {
+			fmt.Fprintln(output, heading(rank))
+			fmt.Fprint(output, renderScheduleText(selected[rank-1].Classes, *color))
+		}
+		return nil
+	case "html":
+		schedulesClasses := [][]ClassEnhanced{}
+		headings := []string{}
+		for _, rank := range ranks {
+			schedulesClasses = append(schedulesClasses, selected[rank-1].Classes)
+			headings = append(headings, heading(rank))
+		}
+		_, err := fmt.Fprint(output, renderSchedulesHTML(schedulesClasses, headings, "Schedules"))
+		return err
+	case "svg":
+		if len(ranks) == 0 {
+			return errors.New("no schedule to render")
+		}
+		_, err := fmt.Fprint(output, renderScheduleSVG(selected[ranks[0]-1].Classes))
+		return err
+	}
+
+	exports := []ScheduleExport{}
+	for _, export := range exportSchedules(selected, 0) {
+		if *rank == 0 || export.Rank == *rank {
+			exports = append(exports, export)
+		}
+	}
+
+	if *format == "csv" {
+		return writeSchedulesCSV(output, exports)
+	}
+
+	encoder := json.NewEncoder(output)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(exports)
+}
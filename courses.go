@@ -0,0 +1,182 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Normalizes a course code so the different ways of writing it compare the same
+// "MATH 8", "math 008" and "MATH008" all become "MATH8", "MATH 005A" becomes "MATH5A"
+func normalizeCourseCode(code string) string {
+	code = strings.ToUpper(code)
+
+	// Only letters and digits matter
+	code = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, code)
+
+	// Subject, then the course number without zero padding, then its suffix
+	numberStart := strings.IndexFunc(code, unicode.IsDigit)
+	if numberStart == -1 {
+		return code
+	}
+
+	subject := code[:numberStart]
+	number := code[numberStart:]
+
+	suffixStart := strings.IndexFunc(number, func(r rune) bool {
+		return !unicode.IsDigit(r)
+	})
+	suffix := ""
+	if suffixStart != -1 {
+		suffix = number[suffixStart:]
+		number = number[:suffixStart]
+	}
+
+	number = strings.TrimLeft(number, "0")
+	if number == "" {
+		number = "0"
+	}
+
+	return subject + number + suffix
+}
+
+// Maps the normalized code of every course that can satisfy a requested course to the requested course
+// A requested course is satisfied by itself and by the courses it is cross-listed with or equivalent to,
+// from both the school's CourseEquivalents and the constraints' CourseEquivalents
+func requestedCourseCodes(constraints UserScheduleConstraints) map[string]string {
+	equivalents := [][]string{}
+	equivalents = append(equivalents, schools[constraints.SchoolId].CourseEquivalents...)
+	equivalents = append(equivalents, constraints.CourseEquivalents...)
+
+	courseCodes := map[string]string{}
+
+	// Courses asked for directly always satisfy themselves
+	for _, courseName := range constraints.Courses {
+		courseCodes[normalizeCourseCode(courseName)] = courseName
+	}
+
+	for _, courseName := range constraints.Courses {
+		for _, group := range equivalents {
+			if !courseInList(courseName, group) {
+				continue
+			}
+
+			for _, equivalent := range group {
+				if _, ok := courseCodes[normalizeCourseCode(equivalent)]; !ok {
+					courseCodes[normalizeCourseCode(equivalent)] = courseName
+				}
+			}
+		}
+	}
+
+	return courseCodes
+}
+
+// Returns true if the course is in the list, comparing normalized course codes
+func courseInList(courseName string, courseNames []string) bool {
+	code := normalizeCourseCode(courseName)
+
+	for _, name := range courseNames {
+		if normalizeCourseCode(name) == code {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Returns the normalized code of the course a class was picked for, its own course unless it was picked as an equivalent
+func (class Class) requestedCourseCode() string {
+	if class.RequestedCourse != "" {
+		return normalizeCourseCode(class.RequestedCourse)
+	}
+
+	return normalizeCourseCode(class.CourseName)
+}
+
+// Returns the normalized code of the course a class was picked for, its own course unless it was picked as an equivalent
+func (class ClassEnhanced) requestedCourseCode() string {
+	if class.RequestedCourse != "" {
+		return normalizeCourseCode(class.RequestedCourse)
+	}
+
+	return normalizeCourseCode(class.CourseName)
+}
+
+// Returns true if a course constraint applies to a class, either through its own course or the course it was picked for
+// An empty course applies to every class
+func courseConstraintApplies(courseName string, classCourseName string, requestedCourseCode string) bool {
+	if courseName == "" {
+		return true
+	}
+
+	code := normalizeCourseCode(courseName)
+	return code == normalizeCourseCode(classCourseName) || code == requestedCourseCode
+}
+
+// Returns the requested courses no section was found for
+func missingCourses(classes []Class, constraints UserScheduleConstraints) []string {
+	found := map[string]bool{}
+	for _, class := range classes {
+		found[class.requestedCourseCode()] = true
+	}
+
+	missing := []string{}
+	for _, courseName := range constraints.Courses {
+		if !found[normalizeCourseCode(courseName)] {
+			missing = append(missing, courseName)
+		}
+	}
+
+	return missing
+}
+
+// Returns up to limit course codes from the class data that look like the given code, best first
+// Used to suggest what the user meant when a requested course does not exist
+func suggestCourseCodes(school School, courseName string, limit int) []string {
+	type Suggestion struct {
+		courseName string
+		similarity float64
+	}
+
+	code := normalizeCourseCode(courseName)
+	seen := map[string]bool{}
+	suggestions := []Suggestion{}
+
+	for _, class := range school.Classes {
+		if seen[class.CourseName] {
+			continue
+		}
+		seen[class.CourseName] = true
+
+		similarity := JaroWinklerDistance(code, normalizeCourseCode(class.CourseName))
+
+		// Courses of the same subject are much more likely to be what was meant
+		if courseSubject(code) == courseSubject(class.CourseName) {
+			similarity += 0.1
+		}
+
+		if similarity >= 0.8 {
+			suggestions = append(suggestions, Suggestion{class.CourseName, similarity})
+		}
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].similarity != suggestions[j].similarity {
+			return suggestions[i].similarity > suggestions[j].similarity
+		}
+		return suggestions[i].courseName < suggestions[j].courseName
+	})
+
+	courseNames := []string{}
+	for i := 0; i < len(suggestions) && i < limit; i++ {
+		courseNames = append(courseNames, suggestions[i].courseName)
+	}
+
+	return courseNames
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeCourseCode(t *testing.T) {
+	tests := []struct {
+		code string
+		want string
+	}{
+		{"MATH 8", "MATH8"},
+		{"math 008", "MATH8"},
+		{"MATH008", "MATH8"},
+		{"MATH 005A", "MATH5A"},
+		{"Math-5a", "MATH5A"},
+		{"  ENGL 1A  ", "ENGL1A"},
+		{"CS 000", "CS0"},
+		{"CS 100", "CS100"},
+		{"MATH 5AH", "MATH5AH"},
+		{"MATH", "MATH"},
+		{"", ""},
+		{"ESPAÑOL 1", "ESPAÑOL1"},
+	}
+
+	for _, test := range tests {
+		if got := normalizeCourseCode(test.code); got != test.want {
+			t.Errorf("normalizeCourseCode(%q) = %q, want %q", test.code, got, test.want)
+		}
+	}
+}
+
+func TestCourseInList(t *testing.T) {
+	courseNames := []string{"MATH 005A", "ENGL 1A"}
+
+	tests := []struct {
+		courseName string
+		want       bool
+	}{
+		{"math 5a", true},
+		{"ENGL001A", true},
+		{"MATH 5B", false},
+		{"MATH 50A", false},
+	}
+
+	for _, test := range tests {
+		if got := courseInList(test.courseName, courseNames); got != test.want {
+			t.Errorf("courseInList(%q) = %v, want %v", test.courseName, got, test.want)
+		}
+	}
+}
+
+func TestSuggestCourseCodes(t *testing.T) {
+	school := School{Classes: []Class{
+		{CourseName: "MATH 5A"},
+		{CourseName: "MATH 5A"},
+		{CourseName: "MATH 5B"},
+		{CourseName: "MATH 55"},
+		{CourseName: "ENGL 1A"},
+		{CourseName: "HIST 7B"},
+	}}
+
+	tests := []struct {
+		courseName string
+		limit      int
+		want       []string
+	}{
+		{"MATH 5C", 5, []string{"MATH 55", "MATH 5A", "MATH 5B"}}, // equally close, by name
+		{"MATH 5C", 2, []string{"MATH 55", "MATH 5A"}},
+		{"ENGL 1", 5, []string{"ENGL 1A"}},
+		{"PHYS 2A", 5, []string{}},
+	}
+
+	for _, test := range tests {
+		if got := suggestCourseCodes(school, test.courseName, test.limit); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("suggestCourseCodes(%q, %d) = %v, want %v", test.courseName, test.limit, got, test.want)
+		}
+	}
+}
+
+func TestFilterCoursesEquivalents(t *testing.T) {
+	school := School{Classes: []Class{
+		{ClassID: "100", CourseName: "ENGL 1A"},
+		{ClassID: "101", CourseName: "ENGL 1AH"},
+		{ClassID: "200", CourseName: "CS 5"},
+		{ClassID: "201", CourseName: "CIS 5"},
+		{ClassID: "300", CourseName: "HIST 7B"},
+		{ClassID: "400", CourseName: "MATH 5A"},
+	}}
+	constraints := UserScheduleConstraints{
+		SchoolId: "pcc",
+		Courses:  []string{"ENGL 1A", "CS 5", "MATH 5A"},
+		CourseEquivalents: [][]string{
+			{"ENGL 1A", "ENGL 1AH"},
+			{"CS 5", "CIS 5"},
+			{"HIST 7A", "HIST 7B"}, // not requested
+		},
+	}
+
+	got := map[string]string{}
+	for _, class := range filterCourses(school, constraints) {
+		got[class.ClassID] = class.RequestedCourse
+	}
+
+	// Sections of the requested course itself have no RequestedCourse
+	want := map[string]string{"100": "", "101": "ENGL 1A", "200": "", "201": "CS 5", "400": ""}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("sections = %v, want %v", got, want)
+	}
+}
+
+func TestFilterCoursesBothEquivalentsRequested(t *testing.T) {
+	school := School{Classes: []Class{
+		{ClassID: "100", CourseName: "ENGL 1A"},
+		{ClassID: "101", CourseName: "ENGL 1AH"},
+	}}
+	constraints := UserScheduleConstraints{
+		SchoolId:          "pcc",
+		Courses:           []string{"ENGL 1A", "ENGL 1AH"},
+		CourseEquivalents: [][]string{{"ENGL 1A", "ENGL 1AH"}},
+	}
+
+	// Asking for both courses means wanting a section of each
+	for _, class := range filterCourses(school, constraints) {
+		if class.RequestedCourse != "" {
+			t.Errorf("section %s was picked for %s", class.ClassID, class.RequestedCourse)
+		}
+	}
+}
+
+func TestCourseConstraintApplies(t *testing.T) {
+	tests := []struct {
+		courseName      string
+		classCourseName string
+		requestedCourse string
+		want            bool
+	}{
+		{"", "ENGL 1AH", "ENGL 1A", true},
+		{"ENGL 1AH", "ENGL 1AH", "ENGL 1A", true},
+		{"ENGL 1A", "ENGL 1AH", "ENGL 1A", true},
+		{"MATH 5A", "ENGL 1AH", "ENGL 1A", false},
+	}
+
+	for _, test := range tests {
+		if got := courseConstraintApplies(test.courseName, test.classCourseName, normalizeCourseCode(test.requestedCourse)); got != test.want {
+			t.Errorf("courseConstraintApplies(%q, %q, %q) = %v, want %v", test.courseName, test.classCourseName, test.requestedCourse, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"strings"
+)
+
+// Returns true if a rate my professor department is the department of the course
+func inCourseDepartment(schoolId string, courseName string, department string) bool {
+	subject := courseSubject(courseName)
+	department = normalizeName(department)
+
+	if subject == "" || department == "" {
+		return false
+	}
+
+	// Subjects the school has no mapping for fall back to departments that start with the subject (e.g. "CHEM" -> "Chemistry")
+	if departments, ok := schools[schoolId].SubjectDepartments[subject]; ok {
+		for _, name := range departments {
+			if normalizeName(name) == department {
+				return true
+			}
+		}
+		return false
+	}
+
+	return strings.HasPrefix(department, normalizeName(subject))
+}
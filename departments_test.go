@@ -0,0 +1,57 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestInCourseDepartment(t *testing.T) {
+	tests := []struct {
+		courseName string
+		department string
+		want       bool
+	}{
+		{"MATH 5A", "Mathematics", true},
+		{"MATH 5A", "math", true},
+		{"MATH 5A", "English", false},
+		{"CIS 5", "Computer Science", true},
+		{"ENGL 1A", "English Literature", false}, // mapped subjects only take their own departments
+		{"ZOOL 1", "Zoology", true},              // unmapped subjects take departments starting with the subject
+		{"ZOOL 1", "Biology", false},
+		{"MATH 5A", "", false},
+		{"", "Mathematics", false},
+	}
+
+	for _, test := range tests {
+		if got := inCourseDepartment("pcc", test.courseName, test.department); got != test.want {
+			t.Errorf("inCourseDepartment(%q, %q) = %v, want %v", test.courseName, test.department, got, test.want)
+		}
+	}
+}
+
+func TestMatchInstructorDepartment(t *testing.T) {
+	johnSmithMath := ProfessorType{Id: 1, FirstName: "John", LastName: "Smith", Department: "Mathematics", OverallRating: 4, TotalRatings: 10}
+	johnSmithEnglish := ProfessorType{Id: 2, FirstName: "John", LastName: "Smith", Department: "English", OverallRating: 3, TotalRatings: 10}
+	jonSmithEnglish := ProfessorType{Id: 3, FirstName: "Jon", LastName: "Smith", Department: "English", OverallRating: 5, TotalRatings: 10}
+
+	tests := []struct {
+		name       string
+		courseName string
+		instructor string
+		professors []ProfessorType
+		wantID     int
+		wantFound  bool
+	}{
+		{"same name, course department wins", "MATH 5A", "John Smith", []ProfessorType{johnSmithEnglish, johnSmithMath}, 1, true},
+		{"same name, other course department wins", "ENGL 1A", "John Smith", []ProfessorType{johnSmithMath, johnSmithEnglish}, 2, true},
+		{"same name, neither department is ambiguous", "HIST 7B", "John Smith", []ProfessorType{johnSmithMath, johnSmithEnglish}, 0, false},
+		{"department wins over a slightly closer name", "MATH 5A", "Jon Smith", []ProfessorType{jonSmithEnglish, johnSmithMath}, 1, true},
+		{"closer name wins without a department", "HIST 7B", "Jon Smith", []ProfessorType{johnSmithMath, jonSmithEnglish}, 3, true},
+	}
+
+	for _, test := range tests {
+		got, _, found := matchInstructor("pcc", test.courseName, test.instructor, test.professors)
+		if found != test.wantFound || got.Id != test.wantID {
+			t.Errorf("%s: matchInstructor = %d, %v, want %d, %v", test.name, got.Id, found, test.wantID, test.wantFound)
+		}
+	}
+}
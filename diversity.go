@@ -0,0 +1,181 @@
+package main
+
+// SelectionMode decides how the schedules returned to the user are picked from the ranked schedules
+type SelectionMode string
+
+const (
+	SelectionRanked  SelectionMode = "ranked"  // the best rated schedules (default)
+	SelectionDiverse SelectionMode = "diverse" // the best rated schedules that differ from each other by MinDifferentSections or their days
+	SelectionMMR     SelectionMode = "mmr"     // maximal marginal relevance, trading rating against difference with DiversityTradeoff
+)
+
+// Used when the constraints do not set them
+const (
+	defaultMinDifferentSections = 2
+	defaultDiversityTradeoff    = 0.7
+	defaultSelectionCount       = 10
+)
+
+// Days of the week a schedule meets on, one bit per day in meetingDayLetters order
+func scheduleDayPattern(schedule Schedule) int {
+	pattern := 0
+
+	for _, class := range schedule.Classes {
+		for _, meetingTime := range class.MeetingTimes {
+			for day, meets := range meetingDays(meetingTime) {
+				if meets {
+					pattern |= 1 << day
+				}
+			}
+		}
+	}
+
+	return pattern
+}
+
+// Number of sections of one schedule that are not in the other
+func differentSections(schedule1 Schedule, schedule2 Schedule) int {
+	classIDs := map[string]bool{}
+	for _, class := range schedule2.Classes {
+		classIDs[class.ClassID] = true
+	}
+
+	different := 0
+	for _, class := range schedule1.Classes {
+		if !classIDs[class.ClassID] {
+			different++
+		}
+	}
+
+	return different
+}
+
+// How alike two schedules are (0 - 1), the share of sections they have in common, halved when they meet on different days
+func scheduleSimilarity(schedule1 Schedule, schedule2 Schedule) float32 {
+	if len(schedule1.Classes) == 0 {
+		return 0
+	}
+
+	similarity := float32(len(schedule1.Classes)-differentSections(schedule1, schedule2)) / float32(len(schedule1.Classes))
+	if scheduleDayPattern(schedule1) != scheduleDayPattern(schedule2) {
+		similarity /= 2
+	}
+
+	return similarity
+}
+
+// Picks up to count schedules out of the ranked schedules (best first) according to the constraints' SelectionMode
+// A count of 0 returns every schedule when ranking and defaultSelectionCount schedules otherwise
+func selectSchedules(schedules []Schedule, constraints UserScheduleConstraints, count int) []Schedule {
+	switch constraints.SelectionMode {
+	case SelectionDiverse:
+		if count <= 0 {
+			count = defaultSelectionCount
+		}
+		return selectDiverseSchedules(schedules, constraints, count)
+	case SelectionMMR:
+		if count <= 0 {
+			count = defaultSelectionCount
+		}
+		return selectMMRSchedules(schedules, constraints, count)
+	default:
+		if count <= 0 || count > len(schedules) {
+			count = len(schedules)
+		}
+		return schedules[:count]
+	}
+}
+
+// Walks the ranked schedules and keeps the ones that differ from every schedule kept so far
+// by at least MinDifferentSections sections or by the days they meet on
+func selectDiverseSchedules(schedules []Schedule, constraints UserScheduleConstraints, count int) []Schedule {
+	minDifferent := constraints.MinDifferentSections
+	if minDifferent <= 0 {
+		minDifferent = defaultMinDifferentSections
+	}
+
+	selected := []Schedule{}
+
+	for _, schedule := range schedules {
+		if len(selected) >= count {
+			break
+		}
+
+		diverse := true
+		for _, other := range selected {
+			if differentSections(schedule, other) < minDifferent && scheduleDayPattern(schedule) == scheduleDayPattern(other) {
+				diverse = false
+				break
+			}
+		}
+
+		if diverse {
+			selected = append(selected, schedule)
+		}
+	}
+
+	return selected
+}
+
+// Maximal marginal relevance: repeatedly picks the schedule with the best mix of a high rating
+// and a low similarity to the schedules already picked
+func selectMMRSchedules(schedules []Schedule, constraints UserScheduleConstraints, count int) []Schedule {
+	tradeoff := float32(defaultDiversityTradeoff)
+	if constraints.DiversityTradeoff != nil {
+		tradeoff = *constraints.DiversityTradeoff
+	}
+
+	if len(schedules) == 0 {
+		return []Schedule{}
+	}
+
+	// Ratings scaled to 0 - 1 so they weigh the same as similarities
+	minRating, maxRating := schedules[0].Rating, schedules[0].Rating
+	for _, schedule := range schedules {
+		if schedule.Rating < minRating {
+			minRating = schedule.Rating
+		}
+		if schedule.Rating > maxRating {
+			maxRating = schedule.Rating
+		}
+	}
+	relevance := func(schedule Schedule) float32 {
+		if maxRating == minRating {
+			return 1
+		}
+		return (schedule.Rating - minRating) / (maxRating - minRating)
+	}
+
+	selected := []Schedule{}
+	picked := make([]bool, len(schedules))
+
+	for len(selected) < count && len(selected) < len(schedules) {
+		best := -1
+		var bestValue float32
+
+		for i, schedule := range schedules {
+			if picked[i] {
+				continue
+			}
+
+			var similarity float32
+			for _, other := range selected {
+				if s := scheduleSimilarity(schedule, other); s > similarity {
+					similarity = s
+				}
+			}
+
+			// Ties go to the better ranked schedule
+			value := tradeoff*relevance(schedule) - (1-tradeoff)*similarity
+			if best == -1 || value > bestValue {
+				best = i
+				bestValue = value
+			}
+		}
+
+		picked[best] = true
+		selected = append(selected, schedules[best])
+	}
+
+	return selected
+}
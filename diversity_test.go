@@ -0,0 +1,97 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// A schedule of sections meeting on Monday, or on the given days when a section id ends with them ("101:TR")
+func diversityTestSchedule(rating float32, classIDs ...string) Schedule {
+	schedule := Schedule{Rating: rating}
+
+	for _, classID := range classIDs {
+		id, days, ok := strings.Cut(classID, ":")
+		if !ok {
+			days = "M"
+		}
+
+		schedule.Classes = append(schedule.Classes, ClassEnhanced{ClassID: id, MeetingTimes: []MeetingTime{testMeeting(days, Time{8, 0}, Time{9, 0})}})
+	}
+
+	return schedule
+}
+
+func scheduleRatings(schedules []Schedule) []float32 {
+	ratings := []float32{}
+	for _, schedule := range schedules {
+		ratings = append(ratings, schedule.Rating)
+	}
+	return ratings
+}
+
+func TestScheduleSimilarity(t *testing.T) {
+	tests := []struct {
+		name      string
+		schedule1 Schedule
+		schedule2 Schedule
+		want      float32
+	}{
+		{"same sections", diversityTestSchedule(0, "1", "2"), diversityTestSchedule(0, "2", "1"), 1},
+		{"half the sections", diversityTestSchedule(0, "1", "2"), diversityTestSchedule(0, "1", "3"), 0.5},
+		{"nothing shared", diversityTestSchedule(0, "1", "2"), diversityTestSchedule(0, "3", "4"), 0},
+		{"half the sections, other days", diversityTestSchedule(0, "1", "2"), diversityTestSchedule(0, "1", "3:TR"), 0.25},
+		{"empty schedule", Schedule{}, diversityTestSchedule(0, "1"), 0},
+	}
+
+	for _, test := range tests {
+		if got := scheduleSimilarity(test.schedule1, test.schedule2); got != test.want {
+			t.Errorf("%s: scheduleSimilarity = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestSelectSchedules(t *testing.T) {
+	// Ranked best first: 9 and 8 only differ by one section, 7 meets on other days, 6 shares nothing with 9
+	schedules := []Schedule{
+		diversityTestSchedule(9, "1", "2", "3"),
+		diversityTestSchedule(8, "1", "2", "4"),
+		diversityTestSchedule(7, "1", "2", "5:TR"),
+		diversityTestSchedule(6, "6", "7", "8"),
+		diversityTestSchedule(5, "6", "7", "9"),
+	}
+
+	ratingOnly, balanced, mostlyDiversity := float32(1), float32(0.5), float32(0.3)
+
+	tests := []struct {
+		name        string
+		constraints UserScheduleConstraints
+		count       int
+		want        []float32
+	}{
+		{"ranked", UserScheduleConstraints{}, 3, []float32{9, 8, 7}},
+		{"ranked, all of them", UserScheduleConstraints{SelectionMode: SelectionRanked}, 0, []float32{9, 8, 7, 6, 5}},
+		{"ranked, more than there are", UserScheduleConstraints{}, 10, []float32{9, 8, 7, 6, 5}},
+		{"diverse", UserScheduleConstraints{SelectionMode: SelectionDiverse}, 10, []float32{9, 7, 6}},
+		{"diverse, limited", UserScheduleConstraints{SelectionMode: SelectionDiverse}, 2, []float32{9, 7}},
+		{"diverse, one different section is enough", UserScheduleConstraints{SelectionMode: SelectionDiverse, MinDifferentSections: 1}, 0, []float32{9, 8, 7, 6, 5}},
+		{"diverse, every section different", UserScheduleConstraints{SelectionMode: SelectionDiverse, MinDifferentSections: 3}, 0, []float32{9, 7, 6}},
+		{"mmr, default leans on the rating", UserScheduleConstraints{SelectionMode: SelectionMMR}, 3, []float32{9, 8, 7}},
+		{"mmr, rating only", UserScheduleConstraints{SelectionMode: SelectionMMR, DiversityTradeoff: &ratingOnly}, 0, []float32{9, 8, 7, 6, 5}},
+		{"mmr, balanced", UserScheduleConstraints{SelectionMode: SelectionMMR, DiversityTradeoff: &balanced}, 5, []float32{9, 6, 7, 8, 5}},
+		{"mmr, mostly diversity", UserScheduleConstraints{SelectionMode: SelectionMMR, DiversityTradeoff: &mostlyDiversity}, 3, []float32{9, 6, 7}},
+	}
+
+	for _, test := range tests {
+		got := scheduleRatings(selectSchedules(schedules, test.constraints, test.count))
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: selected %v, want %v", test.name, got, test.want)
+		}
+	}
+
+	for _, mode := range []SelectionMode{SelectionRanked, SelectionDiverse, SelectionMMR} {
+		if got := selectSchedules([]Schedule{}, UserScheduleConstraints{SelectionMode: mode}, 10); len(got) != 0 {
+			t.Errorf("%s: selected %d schedules out of none", mode, len(got))
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// MeetingExport is one meeting time of an exported section
+type MeetingExport struct {
+	Days      string `json:"days"` // day letters, e.g. "MW"
+	StartTime string `json:"startTime"`
+	EndTime   string `json:"endTime"`
+}
+
+// SectionExport is one section of an exported schedule
+type SectionExport struct {
+	ClassID          string              `json:"classID"`
+	CourseName       string              `json:"courseName"`
+	RequestedCourse  string              `json:"requestedCourse,omitempty"`
+	Instructor       string              `json:"instructor"`
+	InstructorRating float32             `json:"instructorRating"`
+	Rated            bool                `json:"rated"`
+	RatingCount      int                 `json:"ratingCount"`
+	Method           InstructionalMethod `json:"instructionalMethod"`
+	Availability     AvailabilityStatus  `json:"availability"`
+	Meetings         []MeetingExport     `json:"meetings"`
+	Score            SectionScore        `json:"score"`
+}
+
+// ScheduleExport is a ranked schedule with the detail of each of its sections
+type ScheduleExport struct {
+	Rank          int             `json:"rank"` // 1 based
+	Score         float32         `json:"score"`
+	Penalties     float32         `json:"penalties"`
+	IdleMinutes   int             `json:"idleMinutes"`
+	DaysOnCampus  int             `json:"daysOnCampus"`
+	EarliestStart string          `json:"earliestStart"`
+	LatestEnd     string          `json:"latestEnd"`
+	Sections      []SectionExport `json:"sections"`
+}
+
+// Columns of the CSV export of schedules, one row per section of every schedule
+var scheduleCSVColumns = []string{"rank", "scheduleScore", "schedulePenalties", "idleMinutes", "daysOnCampus", "earliestStart", "latestEnd",
+	"classID", "courseName", "requestedCourse", "instructor", "instructorRating", "rated", "ratingCount",
+	"ratingScore", "openSeatsScore", "instructorPreferenceScore", "instructorPenaltyScore", "sectionScore",
+	"instructionalMethod", "availability", "days", "startTimes", "endTimes"}
+
+// Builds the export of the first limit ranked schedules, 0 exports every schedule
+func exportSchedules(schedules []Schedule, limit int) []ScheduleExport {
+	exports := []ScheduleExport{}
+
+	for i, schedule := range schedules {
+		if limit > 0 && i >= limit {
+			break
+		}
+
+		breakdown := schedule.Breakdown
+		export := ScheduleExport{
+			Rank:          i + 1,
+			Score:         schedule.Rating,
+			Penalties:     breakdown.Penalties,
+			IdleMinutes:   breakdown.IdleMinutes,
+			DaysOnCampus:  breakdown.DaysOnCampus,
+			EarliestStart: formatMinutes(timeInMinutes(breakdown.EarliestStart)),
+			LatestEnd:     formatMinutes(timeInMinutes(breakdown.LatestEnd)),
+			Sections:      []SectionExport{},
+		}
+
+		for j, class := range schedule.Classes {
+			meetings := []MeetingExport{}
+			for _, meetingTime := range class.MeetingTimes {
+				days := ""
+				for day, meets := range meetingDays(meetingTime) {
+					if meets {
+						days += meetingDayLetters[day]
+					}
+				}
+
+				meetings = append(meetings, MeetingExport{days, formatMinutes(timeInMinutes(meetingTime.StartTime)), formatMinutes(timeInMinutes(meetingTime.EndTime))})
+			}
+
+			export.Sections = append(export.Sections, SectionExport{
+				ClassID:          class.ClassID,
+				CourseName:       class.CourseName,
+				RequestedCourse:  class.RequestedCourse,
+				Instructor:       class.Instructor,
+				InstructorRating: class.InstructorRating,
+				Rated:            class.Rated,
+				RatingCount:      class.RatingCount,
+				Method:           class.InstructionalMethod,
+				Availability:     class.Availability,
+				Meetings:         meetings,
+				Score:            breakdown.Sections[j].Score,
+			})
+		}
+
+		exports = append(exports, export)
+	}
+
+	return exports
+}
+
+// Writes exported schedules as CSV with a header row, one row per section of every schedule
+// Sections meeting more than once have their days and times separated by ";"
+func writeSchedulesCSV(writer io.Writer, exports []ScheduleExport) error {
+	csvWriter := csv.NewWriter(writer)
+
+	if err := csvWriter.Write(scheduleCSVColumns); err != nil {
+		return err
+	}
+
+	number := func(n float32) string {
+		return fmt.Sprintf("%.2f", n)
+	}
+
+	for _, export := range exports {
+		for _, section := range export.Sections {
+			days := []string{}
+			startTimes := []string{}
+			endTimes := []string{}
+			for _, meeting := range section.Meetings {
+				days = append(days, meeting.Days)
+				startTimes = append(startTimes, meeting.StartTime)
+				endTimes = append(endTimes, meeting.EndTime)
+			}
+
+			record := []string{
+				fmt.Sprint(export.Rank),
+				number(export.Score),
+				number(export.Penalties),
+				fmt.Sprint(export.IdleMinutes),
+				fmt.Sprint(export.DaysOnCampus),
+				export.EarliestStart,
+				export.LatestEnd,
+				section.ClassID,
+				section.CourseName,
+				section.RequestedCourse,
+				section.Instructor,
+				number(section.InstructorRating),
+				fmt.Sprint(section.Rated),
+				fmt.Sprint(section.RatingCount),
+				number(section.Score.Rating),
+				number(section.Score.OpenSeats),
+				number(section.Score.InstructorPreference),
+				number(section.Score.InstructorPenalty),
+				number(section.Score.Total),
+				section.Method.Label(),
+				section.Availability.String(),
+				strings.Join(days, ";"),
+				strings.Join(startTimes, ";"),
+				strings.Join(endTimes, ";"),
+			}
+
+			if err := csvWriter.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
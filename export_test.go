@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"reflect"
+	"testing"
+)
+
+func exportTestSchedules() []Schedule {
+	classes := []ClassEnhanced{
+		{ClassID: "100", CourseName: "MATH 5A", Instructor: "Smith, John", InstructorRating: 4, Rated: true, RatingCount: 12,
+			InstructionalMethod: MethodInPerson, Availability: AvailabilityOpen,
+			MeetingTimes: []MeetingTime{
+				testMeeting("MW", Time{8, 30}, Time{9, 55}),
+				testMeeting("F", Time{10, 0}, Time{11, 0}),
+			}},
+		{ClassID: "200", CourseName: "ENGL 1AH", RequestedCourse: "ENGL 1A", Instructor: "Jane Doe", InstructorRating: 3,
+			InstructionalMethod: MethodOnline, Availability: AvailabilityWaitlisted},
+	}
+
+	schedules := []Schedule{
+		{Classes: classes, Rating: 7},
+		{Classes: classes[:1], Rating: 4},
+	}
+	for i := range schedules {
+		schedules[i].Breakdown = breakdownSchedule(schedules[i].Classes, UserScheduleConstraints{})
+	}
+
+	return schedules
+}
+
+func TestExportSchedules(t *testing.T) {
+	schedules := exportTestSchedules()
+
+	exports := exportSchedules(schedules, 0)
+	if len(exports) != 2 {
+		t.Fatalf("exported %d schedules, want 2", len(exports))
+	}
+
+	first := exports[0]
+	if first.Rank != 1 || first.Score != 7 || first.EarliestStart != "08:30" || first.LatestEnd != "11:00" || first.DaysOnCampus != 3 {
+		t.Errorf("first schedule = %+v", first)
+	}
+	if exports[1].Rank != 2 {
+		t.Errorf("second schedule rank = %d, want 2", exports[1].Rank)
+	}
+
+	wantMeetings := []MeetingExport{{"MW", "08:30", "09:55"}, {"F", "10:00", "11:00"}}
+	if !reflect.DeepEqual(first.Sections[0].Meetings, wantMeetings) {
+		t.Errorf("meetings = %+v, want %+v", first.Sections[0].Meetings, wantMeetings)
+	}
+	if first.Sections[1].RequestedCourse != "ENGL 1A" || len(first.Sections[1].Meetings) != 0 {
+		t.Errorf("second section = %+v", first.Sections[1])
+	}
+	if first.Sections[0].Score.Total != 4 {
+		t.Errorf("section score = %+v, want a total of 4", first.Sections[0].Score)
+	}
+
+	if limited := exportSchedules(schedules, 1); len(limited) != 1 || limited[0].Rank != 1 {
+		t.Errorf("exportSchedules with a limit of 1 = %+v", limited)
+	}
+	if none := exportSchedules(nil, 0); none == nil || len(none) != 0 {
+		t.Errorf("exportSchedules(nil) = %#v, want an empty list", none)
+	}
+}
+
+func TestWriteSchedulesCSV(t *testing.T) {
+	output := bytes.Buffer{}
+	if err := writeSchedulesCSV(&output, exportSchedules(exportTestSchedules(), 0)); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := csv.NewReader(&output).ReadAll()
+	if err != nil {
+		t.Fatalf("CSV does not parse back: %v", err)
+	}
+
+	// Header, then one row per section of every schedule
+	if len(records) != 4 {
+		t.Fatalf("%d rows, want 4", len(records))
+	}
+	if !reflect.DeepEqual(records[0], scheduleCSVColumns) {
+		t.Errorf("header = %v", records[0])
+	}
+
+	row := map[string]string{}
+	for i, column := range scheduleCSVColumns {
+		row[column] = records[1][i]
+	}
+
+	want := map[string]string{
+		"rank":          "1",
+		"scheduleScore": "7.00",
+		"classID":       "100",
+		"instructor":    "Smith, John",
+		"rated":         "true",
+		"ratingCount":   "12",
+		"days":          "MW;F",
+		"startTimes":    "08:30;10:00",
+		"endTimes":      "09:55;11:00",
+		"availability":  "open",
+	}
+	for column, value := range want {
+		if row[column] != value {
+			t.Errorf("%s = %q, want %q", column, row[column], value)
+		}
+	}
+
+	if records[3][0] != "2" || records[3][7] != "100" {
+		t.Errorf("last row = %v, want the section of the second schedule", records[3])
+	}
+}
@@ -0,0 +1,302 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"schedulegenerator/db"
+	"sort"
+	"time"
+)
+
+// FavoriteSchedule is a generated schedule a user saved under a name
+// Saving the same name again adds a new version, older versions are kept so they can be compared
+type FavoriteSchedule struct {
+	UserId             string                  `json:"userId"`
+	Name               string                  `json:"name"`
+	Version            int                     `json:"version"` // 1 for the first save of the name, increases with every save
+	ClassIDs           []string                `json:"classIDs"`
+	Score              float32                 `json:"score"`
+	ClassDataTimestamp int64                   `json:"classDataTimestamp"` // timestamp of the class data the schedule was generated from
+	Constraints        UserScheduleConstraints `json:"constraints"`        // constraints the schedule was generated with
+	SavedAt            int64                   `json:"savedAt"`
+}
+
+// FavoriteComparison is what changed between two versions of a favorite schedule
+type FavoriteComparison struct {
+	FromVersion      int      `json:"fromVersion"`
+	ToVersion        int      `json:"toVersion"`
+	Added            []string `json:"added"`   // class ids only in the newer version
+	Removed          []string `json:"removed"` // class ids only in the older version
+	ScoreChange      float32  `json:"scoreChange"`
+	ClassDataChanged bool     `json:"classDataChanged"` // the versions were generated from different class data
+}
+
+// Fetches every version of every favorite schedule of a user, sorted by name then version
+func fetchFavoriteSchedules(userId string) ([]FavoriteSchedule, error) {
+	// Get collection from database
+	collection, err := db.GetDBCollection("FavoriteSchedules")
+
+	if err != nil {
+		fmt.Println(err)
+		return nil, errors.New("unable to fetch collection from database")
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}, {Key: "version", Value: 1}})
+	cursor, err := collection.Find(context.TODO(), bson.M{"userid": userId}, opts)
+	if err != nil {
+		return nil, errors.New("unable to fetch favorite schedules")
+	}
+
+	// Deserialize result
+	var elems []FavoriteSchedule
+	err = cursor.All(context.TODO(), &elems)
+
+	if err != nil {
+		return nil, errors.New("unable to decode favorite schedules")
+	}
+
+	return elems, nil
+}
+
+// Fetches one version of a favorite schedule of a user, version 0 is the latest
+func fetchFavoriteSchedule(userId string, name string, version int) (FavoriteSchedule, error) {
+	// Get collection from database
+	collection, err := db.GetDBCollection("FavoriteSchedules")
+
+	if err != nil {
+		fmt.Println(err)
+		return FavoriteSchedule{}, errors.New("unable to fetch collection from database")
+	}
+
+	filter := bson.M{"userid": userId, "name": name}
+	if version != 0 {
+		filter["version"] = version
+	}
+
+	opts := options.FindOne().SetSort(bson.M{"version": -1})
+	cursor := collection.FindOne(context.TODO(), filter, opts)
+
+	// Deserialize result
+	var elem FavoriteSchedule
+	err = cursor.Decode(&elem)
+
+	if err != nil {
+		return FavoriteSchedule{}, errors.New("did not find specified favorite schedule")
+	}
+
+	return elem, nil
+}
+
+// Fetches the latest version of every favorite schedule of a school that has at least one of the given sections
+func fetchFavoriteSchedulesWithClasses(schoolId string, classIDs []string) ([]FavoriteSchedule, error) {
+	// Get collection from database
+	collection, err := db.GetDBCollection("FavoriteSchedules")
+
+	if err != nil {
+		fmt.Println(err)
+		return nil, errors.New("unable to fetch collection from database")
+	}
+
+	// Every version is fetched, a favorite whose latest version lost the sections must not match on an older one
+	filter := bson.M{"constraints.schoolid": schoolId}
+	opts := options.Find().SetSort(bson.D{{Key: "userid", Value: 1}, {Key: "name", Value: 1}, {Key: "version", Value: 1}})
+	cursor, err := collection.Find(context.TODO(), filter, opts)
+	if err != nil {
+		return nil, errors.New("unable to fetch favorite schedules")
+	}
+
+	// Deserialize result
+	var elems []FavoriteSchedule
+	err = cursor.All(context.TODO(), &elems)
+
+	if err != nil {
+		return nil, errors.New("unable to decode favorite schedules")
+	}
+
+	return latestFavoritesWithClasses(elems, classIDs), nil
+}
+
+// Keeps the latest version of each favorite schedule, if it has at least one of the given sections
+// Favorites are returned sorted by user and name
+func latestFavoritesWithClasses(favorites []FavoriteSchedule, classIDs []string) []FavoriteSchedule {
+	type favoriteKey struct {
+		userId string
+		name   string
+	}
+
+	latest := map[favoriteKey]FavoriteSchedule{}
+	for _, favorite := range favorites {
+		key := favoriteKey{favorite.UserId, favorite.Name}
+		if previous, ok := latest[key]; !ok || favorite.Version > previous.Version {
+			latest[key] = favorite
+		}
+	}
+
+	matching := []FavoriteSchedule{}
+	for _, favorite := range latest {
+		for _, classID := range favorite.ClassIDs {
+			if classIDInList(classID, classIDs) {
+				matching = append(matching, favorite)
+				break
+			}
+		}
+	}
+
+	sort.Slice(matching, func(i, j int) bool {
+		if matching[i].UserId != matching[j].UserId {
+			return matching[i].UserId < matching[j].UserId
+		}
+		return matching[i].Name < matching[j].Name
+	})
+
+	return matching
+}
+
+// Saves a favorite schedule as the next version of the user's favorite with the same name
+// Returns the favorite as saved, with its version and save time
+func saveFavoriteSchedule(favorite FavoriteSchedule) (FavoriteSchedule, error) {
+	if favorite.UserId == "" || favorite.Name == "" {
+		return FavoriteSchedule{}, errors.New("a favorite schedule needs a user id and a name")
+	}
+
+	if len(favorite.ClassIDs) == 0 {
+		return FavoriteSchedule{}, errors.New("a favorite schedule needs at least one class")
+	}
+
+	favorite.Version = 1
+	if previous, err := fetchFavoriteSchedule(favorite.UserId, favorite.Name, 0); err == nil {
+		favorite.Version = previous.Version + 1
+	}
+	favorite.SavedAt = time.Now().Unix()
+
+	// Get collection from database
+	collection, err := db.GetDBCollection("FavoriteSchedules")
+
+	if err != nil {
+		fmt.Println(err)
+		return FavoriteSchedule{}, errors.New("unable to fetch collection from database")
+	}
+
+	_, err = collection.InsertOne(context.TODO(), favorite)
+	if err != nil {
+		return FavoriteSchedule{}, errors.New("unable to save favorite schedule")
+	}
+
+	return favorite, nil
+}
+
+// Removes one version of a favorite schedule of a user, version 0 removes every version
+func deleteFavoriteSchedule(userId string, name string, version int) error {
+	// Get collection from database
+	collection, err := db.GetDBCollection("FavoriteSchedules")
+
+	if err != nil {
+		fmt.Println(err)
+		return errors.New("unable to fetch collection from database")
+	}
+
+	filter := bson.M{"userid": userId, "name": name}
+	if version != 0 {
+		filter["version"] = version
+	}
+
+	result, err := collection.DeleteMany(context.TODO(), filter)
+	if err != nil {
+		return errors.New("unable to delete favorite schedule")
+	}
+
+	if result.DeletedCount == 0 {
+		return errors.New("did not find specified favorite schedule")
+	}
+
+	return nil
+}
+
+// Compares two versions of a favorite schedule
+func compareFavoriteSchedules(from FavoriteSchedule, to FavoriteSchedule) FavoriteComparison {
+	comparison := FavoriteComparison{
+		FromVersion:      from.Version,
+		ToVersion:        to.Version,
+		Added:            []string{},
+		Removed:          []string{},
+		ScoreChange:      to.Score - from.Score,
+		ClassDataChanged: from.ClassDataTimestamp != to.ClassDataTimestamp,
+	}
+
+	for _, classID := range to.ClassIDs {
+		if !classIDInList(classID, from.ClassIDs) {
+			comparison.Added = append(comparison.Added, classID)
+		}
+	}
+
+	for _, classID := range from.ClassIDs {
+		if !classIDInList(classID, to.ClassIDs) {
+			comparison.Removed = append(comparison.Removed, classID)
+		}
+	}
+
+	sort.Strings(comparison.Added)
+	sort.Strings(comparison.Removed)
+
+	return comparison
+}
+
+// Returns the sections of a saved schedule from the class data of its school
+// The school's instructional methods must already be resolved (see normalizeInstructionalMethods)
+func savedScheduleClasses(school School, classIDs []string) ([]ClassEnhanced, error) {
+	classes := []ClassEnhanced{}
+
+	for _, classID := range classIDs {
+		found := false
+		for _, class := range school.Classes {
+			if class.ClassID == classID {
+				classes = append(classes, enhanceClass(class, -1))
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return nil, fmt.Errorf("class %s was not found", classID)
+		}
+	}
+
+	return classes, nil
+}
+
+// Scores a saved schedule the way generated schedules are scored, against the class data it was made from
+// Its sections are rated as if they were locked, so the rating constraints rate them without leaving any out
+// The school's instructional methods must already be resolved (see normalizeInstructionalMethods)
+func scoreSavedSchedule(school School, classIDs []string, professorsExport ProfessorExport, aliases []InstructorAlias, constraints UserScheduleConstraints) (float32, error) {
+	courseCodes := requestedCourseCodes(constraints)
+	classes := []Class{}
+
+	for _, classID := range classIDs {
+		found := false
+		for _, class := range school.Classes {
+			if class.ClassID == classID {
+				code := normalizeCourseCode(class.CourseName)
+				if requestedCourse, ok := courseCodes[code]; ok && normalizeCourseCode(requestedCourse) != code {
+					class.RequestedCourse = requestedCourse
+				}
+				classes = append(classes, class)
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return 0, fmt.Errorf("class %s was not found", classID)
+		}
+	}
+
+	locked := constraints
+	locked.LockedClassIDs = classIDs
+
+	enhancedClasses := rateClasses(constraints.SchoolId, classes, professorsExport, aliases, locked)
+
+	return scoreSchedule(enhancedClasses, scoreSections(enhancedClasses, constraints)), nil
+}
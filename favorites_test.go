@@ -0,0 +1,101 @@
+package main
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestScoreSavedSchedule(t *testing.T) {
+	constraints := revalidationConstraints()
+	constraints.Courses = []string{"MATH 5A", "ENGL 1A"}
+	constraints.MinRating = 0
+
+	professors := ProfessorExport{SchoolId: "pcc", Timestamp: -3, Professors: []ProfessorType{
+		{Id: 1, FirstName: "John", LastName: "Smith", OverallRating: 4, TotalRatings: 20},
+		{Id: 2, FirstName: "Jane", LastName: "Doe", OverallRating: 4.5, TotalRatings: 20},
+	}}
+
+	school := School{Timestamp: 1, Classes: []Class{
+		testSection("100", "MATH 5A", "John Smith", 8),
+		testSection("200", "ENGL 1A", "Jane Doe", 10),
+		testSection("300", "ENGL 1A", "Nobody Known", 12),
+	}}
+
+	t.Run("same score as the generated schedule", func(t *testing.T) {
+		schedules := generateSchedule(rateClasses("pcc", school.Classes, professors, nil, constraints), constraints)
+		if len(schedules) != 1 {
+			t.Fatalf("generated %d schedules, want 1", len(schedules))
+		}
+
+		score, err := scoreSavedSchedule(school, []string{"100", "200"}, professors, nil, constraints)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(float64(score-schedules[0].Rating)) > 0.0001 {
+			t.Errorf("score = %.4f, want %.4f", score, schedules[0].Rating)
+		}
+	})
+
+	t.Run("unrated section is scored, not left out", func(t *testing.T) {
+		withRated, err := scoreSavedSchedule(school, []string{"100", "200"}, professors, nil, constraints)
+		if err != nil {
+			t.Fatal(err)
+		}
+		withUnrated, err := scoreSavedSchedule(school, []string{"100", "300"}, professors, nil, constraints)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if withUnrated >= withRated {
+			t.Errorf("score with the unrated section = %.4f, want less than %.4f", withUnrated, withRated)
+		}
+	})
+
+	t.Run("unknown section", func(t *testing.T) {
+		if _, err := scoreSavedSchedule(school, []string{"100", "999"}, professors, nil, constraints); err == nil {
+			t.Error("scoreSavedSchedule accepted a section that is not in the class data")
+		}
+	})
+}
+
+func TestCompareFavoriteSchedules(t *testing.T) {
+	from := FavoriteSchedule{Version: 1, ClassIDs: []string{"100", "200", "300"}, Score: 10, ClassDataTimestamp: 1}
+	to := FavoriteSchedule{Version: 3, ClassIDs: []string{"500", "100", "400"}, Score: 12.5, ClassDataTimestamp: 2}
+
+	want := FavoriteComparison{
+		FromVersion:      1,
+		ToVersion:        3,
+		Added:            []string{"400", "500"},
+		Removed:          []string{"200", "300"},
+		ScoreChange:      2.5,
+		ClassDataChanged: true,
+	}
+
+	if got := compareFavoriteSchedules(from, to); !reflect.DeepEqual(got, want) {
+		t.Errorf("compareFavoriteSchedules = %+v, want %+v", got, want)
+	}
+}
+
+func TestLatestFavoritesWithClasses(t *testing.T) {
+	favorites := []FavoriteSchedule{
+		{UserId: "ann", Name: "fall", Version: 1, ClassIDs: []string{"100", "200"}},
+		{UserId: "ann", Name: "fall", Version: 2, ClassIDs: []string{"300"}}, // latest no longer has 100
+		{UserId: "bob", Name: "fall", Version: 2, ClassIDs: []string{"100"}},
+		{UserId: "bob", Name: "fall", Version: 1, ClassIDs: []string{"400"}},
+		{UserId: "ann", Name: "backup", Version: 1, ClassIDs: []string{"200"}},
+		{UserId: "cid", Name: "other", Version: 1, ClassIDs: []string{"500"}},
+	}
+
+	got := latestFavoritesWithClasses(favorites, []string{"100", "200"})
+
+	names := []string{}
+	for _, favorite := range got {
+		names = append(names, favorite.UserId+"/"+favorite.Name)
+	}
+	if want := []string{"ann/backup", "bob/fall"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("favorites = %v, want %v", names, want)
+	}
+	if len(got) == 2 && got[1].Version != 2 {
+		t.Errorf("bob/fall version = %d, want the latest (2)", got[1].Version)
+	}
+}
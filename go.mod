@@ -1,8 +1,11 @@
-module main
+module schedulegenerator
 
 go 1.18
 
-require go.mongodb.org/mongo-driver v1.9.1
+require (
+	go.mongodb.org/mongo-driver v1.9.1
+	golang.org/x/text v0.3.5
+)
 
 require (
 	github.com/go-stack/stack v1.8.0 // indirect
@@ -15,5 +18,4 @@ require (
 	github.com/youmark/pkcs8 v0.0.0-20181117223130-1be2e3e5546d // indirect
 	golang.org/x/crypto v0.0.0-20201216223049-8b5274cf687f // indirect
 	golang.org/x/sync v0.0.0-20190911185100-cd5d95a43a6e // indirect
-	golang.org/x/text v0.3.5 // indirect
 )
@@ -0,0 +1,411 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+)
+
+// Colors given to courses in the order they appear in a schedule, repeated if there are more courses
+var gridColors = []string{"#4e79a7", "#f28e2b", "#59a14f", "#e15759", "#b07aa1", "#76b7b2", "#edc948", "#9c755f", "#ff9da7", "#bab0ac"}
+
+// ANSI background colors for the terminal, in the same order as gridColors
+var gridTerminalColors = []string{"44", "43", "42", "41", "45", "46", "103", "100", "105", "47"}
+
+// Day names for grid headers, in meetingDayLetters order
+var gridDayNames = []string{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"}
+
+// Minutes per row of the text grid
+const gridTextSlot = 30
+
+// gridBlock is one meeting of a section on one day of the week
+type gridBlock struct {
+	class ClassEnhanced
+	day   int // in meetingDayLetters order
+	start int // minutes since midnight
+	end   int
+	color int // index in gridColors
+	lane  int // position among the blocks it overlaps with, 0 when it overlaps nothing
+	lanes int // number of side by side lanes its group of overlapping blocks needs, 1 when it overlaps nothing
+}
+
+// gridLayout is everything the renderers need to draw a schedule
+type gridLayout struct {
+	blocks      []gridBlock
+	firstHour   int             // first hour shown
+	lastHour    int             // hour the grid ends at
+	unscheduled []ClassEnhanced // sections without meeting times, listed under the grid
+	courses     []string        // courses in color order
+	conflicts   [][2]gridBlock  // pairs of blocks meeting at the same time on the same day
+}
+
+// Lays out the sections of a schedule on a week, the grid covers whole hours from the earliest start to the latest end
+func layoutScheduleGrid(classes []ClassEnhanced) gridLayout {
+	layout := gridLayout{firstHour: 24, lastHour: 0, blocks: []gridBlock{}, unscheduled: []ClassEnhanced{}, courses: []string{}}
+	colors := map[string]int{}
+
+	for _, class := range classes {
+		color, ok := colors[class.CourseName]
+		if !ok {
+			color = len(layout.courses) % len(gridColors)
+			colors[class.CourseName] = color
+			layout.courses = append(layout.courses, class.CourseName)
+		}
+
+		if len(class.MeetingTimes) == 0 {
+			layout.unscheduled = append(layout.unscheduled, class)
+			continue
+		}
+
+		for _, meetingTime := range class.MeetingTimes {
+			start := timeInMinutes(meetingTime.StartTime)
+			end := timeInMinutes(meetingTime.EndTime)
+
+			for day, meets := range meetingDays(meetingTime) {
+				if meets {
+					layout.blocks = append(layout.blocks, gridBlock{class: class, day: day, start: start, end: end, color: color})
+				}
+			}
+
+			if start/60 < layout.firstHour {
+				layout.firstHour = start / 60
+			}
+			if (end+59)/60 > layout.lastHour {
+				layout.lastHour = (end + 59) / 60
+			}
+		}
+	}
+
+	// Nothing meets, show a regular school day
+	if layout.firstHour >= layout.lastHour {
+		layout.firstHour = 8
+		layout.lastHour = 17
+	}
+
+	assignGridLanes(layout.blocks)
+
+	for i, block := range layout.blocks {
+		for _, other := range layout.blocks[i+1:] {
+			if block.day == other.day && block.start < other.end && other.start < block.end {
+				layout.conflicts = append(layout.conflicts, [2]gridBlock{block, other})
+			}
+		}
+	}
+
+	return layout
+}
+
+// Puts the blocks of a day that overlap side by side: every block gets the first lane that is free when it starts,
+// and all the blocks of a group of overlapping blocks share the number of lanes the group needs
+func assignGridLanes(blocks []gridBlock) {
+	order := make([]int, len(blocks))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		if blocks[order[i]].day != blocks[order[j]].day {
+			return blocks[order[i]].day < blocks[order[j]].day
+		}
+		return blocks[order[i]].start < blocks[order[j]].start
+	})
+
+	group := []int{}    // blocks of the current group
+	laneEnds := []int{} // end of the last block of each lane of the current group
+	groupEnd := 0
+
+	closeGroup := func() {
+		for _, i := range group {
+			blocks[i].lanes = len(laneEnds)
+		}
+		group = group[:0]
+		laneEnds = laneEnds[:0]
+	}
+
+	for n, i := range order {
+		block := &blocks[i]
+		if n > 0 && (block.day != blocks[order[n-1]].day || block.start >= groupEnd) {
+			closeGroup()
+		}
+
+		lane := 0
+		for lane < len(laneEnds) && laneEnds[lane] > block.start {
+			lane++
+		}
+		if lane == len(laneEnds) {
+			laneEnds = append(laneEnds, block.end)
+		} else {
+			laneEnds[lane] = block.end
+		}
+		block.lane = lane
+
+		group = append(group, i)
+		if len(group) == 1 || block.end > groupEnd {
+			groupEnd = block.end
+		}
+	}
+	closeGroup()
+}
+
+// Formats minutes since midnight as "HH:MM"
+func formatMinutes(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
+
+// Label of the time bar of a block, e.g. "08:30-09:55"
+func (block gridBlock) timeLabel() string {
+	return formatMinutes(block.start) + "-" + formatMinutes(block.end)
+}
+
+// Renders a schedule as a plain text table with a column per day and a row per half hour
+// With color set, each course gets an ANSI background color
+// Cells where sections overlap are marked as conflicts and the sections are listed under the table
+func renderScheduleText(classes []ClassEnhanced, color bool) string {
+	const width = 12
+	layout := layoutScheduleGrid(classes)
+	text := strings.Builder{}
+
+	cell := func(content string, block *gridBlock) string {
+		if runes := []rune(content); len(runes) > width-1 {
+			content = string(runes[:width-1])
+		}
+		content = fmt.Sprintf(" %-*s", width-1, content)
+
+		if color && block != nil {
+			return "\x1b[" + gridTerminalColors[block.color] + ";97m" + content + "\x1b[0m"
+		}
+		return content
+	}
+
+	border := "+" + strings.Repeat("-", 6) + strings.Repeat("+"+strings.Repeat("-", width), len(gridDayNames)) + "+\n"
+
+	text.WriteString(border)
+	text.WriteString("|      ")
+	for _, day := range gridDayNames {
+		text.WriteString("|" + cell(day, nil))
+	}
+	text.WriteString("|\n")
+	text.WriteString(border)
+
+	for slot := layout.firstHour * 60; slot < layout.lastHour*60; slot += gridTextSlot {
+		label := ""
+		if slot%60 == 0 {
+			label = formatMinutes(slot)
+		}
+		text.WriteString(fmt.Sprintf("| %-5s", label))
+
+		for day := range gridDayNames {
+			var block *gridBlock
+			overlapping := 0
+			for i := range layout.blocks {
+				if layout.blocks[i].day == day && layout.blocks[i].start < slot+gridTextSlot && layout.blocks[i].end > slot {
+					if block == nil {
+						block = &layout.blocks[i]
+					}
+					overlapping++
+				}
+			}
+
+			if block == nil {
+				text.WriteString("|" + cell("", nil))
+				continue
+			}
+			if overlapping > 1 {
+				text.WriteString("|" + cell("!CONFLICT", nil))
+				continue
+			}
+
+			// Course on the first row of the bar, then its times and class id
+			firstSlot := block.start - (block.start-layout.firstHour*60)%gridTextSlot
+			content := ""
+			switch (slot - firstSlot) / gridTextSlot {
+			case 0:
+				content = block.class.CourseName
+			case 1:
+				content = block.timeLabel()
+			case 2:
+				content = "#" + block.class.ClassID
+			}
+			if !color && content == "" {
+				content = "|"
+			}
+
+			text.WriteString("|" + cell(content, block))
+		}
+
+		text.WriteString("|\n")
+	}
+	text.WriteString(border)
+
+	for _, class := range layout.unscheduled {
+		text.WriteString(fmt.Sprintf("No meeting times: %s #%s (%s)\n", class.CourseName, class.ClassID, class.InstructionalMethod.Label()))
+	}
+
+	for _, conflict := range layout.conflicts {
+		text.WriteString(fmt.Sprintf("Conflict on %s: %s #%s %s and %s #%s %s\n", gridDayNames[conflict[0].day],
+			conflict[0].class.CourseName, conflict[0].class.ClassID, conflict[0].timeLabel(),
+			conflict[1].class.CourseName, conflict[1].class.ClassID, conflict[1].timeLabel()))
+	}
+
+	return text.String()
+}
+
+// Renders a schedule as a standalone HTML page with a column per day and a colored, labeled bar per meeting
+func renderScheduleHTML(classes []ClassEnhanced, title string) string {
+	return renderSchedulesHTML([][]ClassEnhanced{classes}, []string{""}, title)
+}
+
+// Renders schedules as one standalone HTML page, each one under its heading (none if empty) as in renderScheduleHTML
+func renderSchedulesHTML(schedules [][]ClassEnhanced, headings []string, title string) string {
+	page := strings.Builder{}
+
+	page.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	page.WriteString("<title>" + html.EscapeString(title) + "</title>\n")
+	page.WriteString(`<style>
+body { font-family: sans-serif; margin: 16px; }
+.grid { display: flex; }
+.column { position: relative; flex: 1; border-left: 1px solid #ddd; }
+.times { flex: 0 0 48px; }
+.header { height: 24px; text-align: center; font-weight: bold; }
+.hour { position: absolute; left: 0; right: 0; border-top: 1px solid #eee; font-size: 11px; color: #777; }
+.bar { position: absolute; left: 2px; right: 2px; border-radius: 4px; padding: 2px 4px; color: #fff; font-size: 11px; overflow: hidden; box-sizing: border-box; }
+.bar b { display: block; }
+</style>
+</head>
+<body>
+`)
+	page.WriteString("<h1>" + html.EscapeString(title) + "</h1>\n")
+
+	for i, classes := range schedules {
+		if headings[i] != "" {
+			page.WriteString("<h2>" + html.EscapeString(headings[i]) + "</h2>\n")
+		}
+		writeScheduleGridHTML(&page, classes)
+	}
+
+	page.WriteString("</body>\n</html>\n")
+
+	return page.String()
+}
+
+// Writes the grid of a schedule of an HTML page
+func writeScheduleGridHTML(page *strings.Builder, classes []ClassEnhanced) {
+	const hourHeight = 48 // pixels
+	layout := layoutScheduleGrid(classes)
+	height := (layout.lastHour - layout.firstHour) * hourHeight
+
+	page.WriteString("<div class=\"grid\">\n")
+
+	// Hour labels
+	page.WriteString(fmt.Sprintf("<div class=\"column times\"><div class=\"header\"></div><div style=\"position: relative; height: %dpx\">\n", height))
+	for hour := layout.firstHour; hour < layout.lastHour; hour++ {
+		page.WriteString(fmt.Sprintf("<div class=\"hour\" style=\"top: %dpx\">%s</div>\n", (hour-layout.firstHour)*hourHeight, formatMinutes(hour*60)))
+	}
+	page.WriteString("</div></div>\n")
+
+	for day, dayName := range gridDayNames {
+		page.WriteString(fmt.Sprintf("<div class=\"column\"><div class=\"header\">%s</div><div style=\"position: relative; height: %dpx\">\n", dayName, height))
+		for hour := layout.firstHour; hour < layout.lastHour; hour++ {
+			page.WriteString(fmt.Sprintf("<div class=\"hour\" style=\"top: %dpx\"></div>\n", (hour-layout.firstHour)*hourHeight))
+		}
+
+		for _, block := range layout.blocks {
+			if block.day != day {
+				continue
+			}
+
+			top := (block.start - layout.firstHour*60) * hourHeight / 60
+			barHeight := (block.end - block.start) * hourHeight / 60
+
+			// Overlapping sections share the column
+			lane := ""
+			if block.lanes > 1 {
+				lane = fmt.Sprintf("left: calc(%.2f%% + 2px); right: auto; width: calc(%.2f%% - 4px); ",
+					float64(block.lane)*100/float64(block.lanes), 100/float64(block.lanes))
+			}
+
+			page.WriteString(fmt.Sprintf("<div class=\"bar\" style=\"%stop: %dpx; height: %dpx; background: %s\" title=\"%s\"><b>%s</b>%s<br>#%s</div>\n",
+				lane, top, barHeight, gridColors[block.color], html.EscapeString(block.class.Instructor),
+				html.EscapeString(block.class.CourseName), block.timeLabel(), html.EscapeString(block.class.ClassID)))
+		}
+
+		page.WriteString("</div></div>\n")
+	}
+	page.WriteString("</div>\n")
+
+	if len(layout.unscheduled) > 0 {
+		page.WriteString("<p>No meeting times:</p>\n<ul>\n")
+		for _, class := range layout.unscheduled {
+			page.WriteString(fmt.Sprintf("<li>%s #%s (%s)</li>\n", html.EscapeString(class.CourseName), html.EscapeString(class.ClassID), class.InstructionalMethod.Label()))
+		}
+		page.WriteString("</ul>\n")
+	}
+}
+
+// Renders a schedule as an SVG image with a column per day and a colored, labeled bar per meeting
+func renderScheduleSVG(classes []ClassEnhanced) string {
+	const (
+		hourHeight  = 48 // pixels
+		columnWidth = 110
+		timeWidth   = 48
+		headerSize  = 24
+		lineHeight  = 13
+	)
+
+	layout := layoutScheduleGrid(classes)
+	gridHeight := (layout.lastHour - layout.firstHour) * hourHeight
+	width := timeWidth + columnWidth*len(gridDayNames)
+	height := headerSize + gridHeight + lineHeight*len(layout.unscheduled)
+	if len(layout.unscheduled) > 0 {
+		height += lineHeight
+	}
+
+	svg := strings.Builder{}
+	svg.WriteString(fmt.Sprintf("<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\" font-family=\"sans-serif\" font-size=\"11\">\n", width, height, width, height))
+	svg.WriteString(fmt.Sprintf("<rect width=\"%d\" height=\"%d\" fill=\"#fff\"/>\n", width, height))
+
+	for hour := layout.firstHour; hour <= layout.lastHour; hour++ {
+		y := headerSize + (hour-layout.firstHour)*hourHeight
+		svg.WriteString(fmt.Sprintf("<line x1=\"%d\" y1=\"%d\" x2=\"%d\" y2=\"%d\" stroke=\"#eee\"/>\n", timeWidth, y, width, y))
+		if hour < layout.lastHour {
+			svg.WriteString(fmt.Sprintf("<text x=\"4\" y=\"%d\" fill=\"#777\">%s</text>\n", y+lineHeight, formatMinutes(hour*60)))
+		}
+	}
+
+	for day, dayName := range gridDayNames {
+		x := timeWidth + day*columnWidth
+		svg.WriteString(fmt.Sprintf("<line x1=\"%d\" y1=\"0\" x2=\"%d\" y2=\"%d\" stroke=\"#ddd\"/>\n", x, x, headerSize+gridHeight))
+		svg.WriteString(fmt.Sprintf("<text x=\"%d\" y=\"16\" text-anchor=\"middle\" font-weight=\"bold\">%s</text>\n", x+columnWidth/2, dayName))
+	}
+
+	for _, block := range layout.blocks {
+		// Overlapping sections share the column
+		laneWidth := columnWidth / block.lanes
+		x := timeWidth + block.day*columnWidth + block.lane*laneWidth + 2
+		y := headerSize + (block.start-layout.firstHour*60)*hourHeight/60
+		barHeight := (block.end - block.start) * hourHeight / 60
+
+		svg.WriteString("<g>\n")
+		svg.WriteString(fmt.Sprintf("<title>%s #%s, %s</title>\n", html.EscapeString(block.class.CourseName), html.EscapeString(block.class.ClassID), html.EscapeString(block.class.Instructor)))
+		svg.WriteString(fmt.Sprintf("<rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" rx=\"4\" fill=\"%s\"/>\n", x, y, laneWidth-4, barHeight, gridColors[block.color]))
+
+		labels := []string{block.class.CourseName, block.timeLabel(), "#" + block.class.ClassID}
+		for i, label := range labels {
+			// Only the labels that fit in the bar
+			if (i+1)*lineHeight > barHeight {
+				break
+			}
+			svg.WriteString(fmt.Sprintf("<text x=\"%d\" y=\"%d\" fill=\"#fff\">%s</text>\n", x+4, y+(i+1)*lineHeight, html.EscapeString(label)))
+		}
+		svg.WriteString("</g>\n")
+	}
+
+	for i, class := range layout.unscheduled {
+		y := headerSize + gridHeight + (i+1)*lineHeight
+		svg.WriteString(fmt.Sprintf("<text x=\"4\" y=\"%d\">No meeting times: %s #%s (%s)</text>\n", y, html.EscapeString(class.CourseName), html.EscapeString(class.ClassID), class.InstructionalMethod.Label()))
+	}
+
+	svg.WriteString("</svg>\n")
+
+	return svg.String()
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func gridTestClasses() []ClassEnhanced {
+	return []ClassEnhanced{
+		enhanceClass(Class{ClassID: "100", CourseName: "MATH 5A", Instructor: "John Smith",
+			MeetingTimes: []MeetingTime{testMeeting("MW", Time{8, 30}, Time{9, 55})}}, -1),
+		enhanceClass(Class{ClassID: "200", CourseName: "ESPAÑOL AVANZADO", Instructor: "José Peña",
+			MeetingTimes: []MeetingTime{testMeeting("T", Time{13, 0}, Time{14, 30})}}, -1),
+		enhanceClass(Class{ClassID: "300", CourseName: "ENGL 1A", Instructor: "Jane Doe"}, -1),
+	}
+}
+
+func TestLayoutScheduleGrid(t *testing.T) {
+	layout := layoutScheduleGrid(gridTestClasses())
+
+	if layout.firstHour != 8 || layout.lastHour != 15 {
+		t.Errorf("hours = %d - %d, want 8 - 15", layout.firstHour, layout.lastHour)
+	}
+	if len(layout.blocks) != 3 {
+		t.Errorf("%d blocks, want 3", len(layout.blocks))
+	}
+	if len(layout.unscheduled) != 1 || layout.unscheduled[0].ClassID != "300" {
+		t.Errorf("unscheduled = %v, want section 300", layout.unscheduled)
+	}
+
+	empty := layoutScheduleGrid(nil)
+	if empty.firstHour != 8 || empty.lastHour != 17 {
+		t.Errorf("hours of an empty schedule = %d - %d, want 8 - 17", empty.firstHour, empty.lastHour)
+	}
+}
+
+func TestRenderScheduleText(t *testing.T) {
+	text := renderScheduleText(gridTestClasses(), false)
+
+	if !utf8.ValidString(text) {
+		t.Fatal("text grid is not valid UTF-8")
+	}
+
+	lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+	width := utf8.RuneCountInString(lines[0])
+	for _, line := range lines {
+		if strings.HasPrefix(line, "No meeting times") {
+			continue
+		}
+		if utf8.RuneCountInString(line) != width {
+			t.Errorf("line %q is %d characters wide, want %d", line, utf8.RuneCountInString(line), width)
+		}
+	}
+
+	for _, want := range []string{"| 08:00|", "MATH 5A", "ESPAÑOL AVA", "08:30-09:55", "#100", "No meeting times: ENGL 1A #300"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("text grid is missing %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestRenderSchedulesHTML(t *testing.T) {
+	page := renderSchedulesHTML([][]ClassEnhanced{gridTestClasses(), gridTestClasses()[:1]}, []string{"Schedule #1", "Schedule #2"}, "Schedules")
+
+	if strings.Count(page, "<div class=\"grid\">") != 2 {
+		t.Error("page does not have a grid per schedule")
+	}
+	for _, want := range []string{"<title>Schedules</title>", "<h2>Schedule #1</h2>", "<h2>Schedule #2</h2>", "ESPAÑOL AVANZADO", "</html>"} {
+		if !strings.Contains(page, want) {
+			t.Errorf("page is missing %q", want)
+		}
+	}
+
+	single := renderScheduleHTML(gridTestClasses(), "Fall <plan>")
+	if strings.Contains(single, "<h2>") || !strings.Contains(single, "<h1>Fall &lt;plan&gt;</h1>") {
+		t.Error("single schedule page has a schedule heading or an unescaped title")
+	}
+}
+
+func TestRenderScheduleSVG(t *testing.T) {
+	svg := renderScheduleSVG(gridTestClasses())
+
+	decoder := xml.NewDecoder(strings.NewReader(svg))
+	for {
+		_, err := decoder.Token()
+		if err != nil {
+			if err.Error() != "EOF" {
+				t.Fatalf("SVG is not well formed: %v", err)
+			}
+			break
+		}
+	}
+
+	if strings.Count(svg, "<rect ") != 4 {
+		t.Errorf("SVG has %d rects, want a background and 3 bars", strings.Count(svg, "<rect "))
+	}
+}
+
+func gridOverlappingClasses() []ClassEnhanced {
+	monday := func(start Time, end Time) []MeetingTime {
+		return []MeetingTime{testMeeting("M", start, end)}
+	}
+
+	return []ClassEnhanced{
+		{ClassID: "100", CourseName: "MATH 5A", MeetingTimes: monday(Time{8, 0}, Time{9, 0})},
+		{ClassID: "200", CourseName: "ENGL 1A", MeetingTimes: monday(Time{8, 30}, Time{9, 30})},
+		{ClassID: "300", CourseName: "HIST 7B", MeetingTimes: monday(Time{9, 0}, Time{10, 0})},
+		{ClassID: "400", CourseName: "ART 10", MeetingTimes: []MeetingTime{testMeeting("T", Time{8, 0}, Time{9, 0})}},
+	}
+}
+
+func TestLayoutScheduleGridOverlaps(t *testing.T) {
+	layout := layoutScheduleGrid(gridOverlappingClasses())
+
+	want := map[string][2]int{"100": {0, 2}, "200": {1, 2}, "300": {0, 2}, "400": {0, 1}} // class id -> lane, lanes
+	for _, block := range layout.blocks {
+		if got := [2]int{block.lane, block.lanes}; got != want[block.class.ClassID] {
+			t.Errorf("section %s is in lane %d of %d, want %v", block.class.ClassID, block.lane, block.lanes, want[block.class.ClassID])
+		}
+	}
+
+	conflicts := []string{}
+	for _, conflict := range layout.conflicts {
+		conflicts = append(conflicts, conflict[0].class.ClassID+"/"+conflict[1].class.ClassID)
+	}
+	if strings.Join(conflicts, " ") != "100/200 200/300" {
+		t.Errorf("conflicts = %v, want 100/200 200/300", conflicts)
+	}
+}
+
+func TestRenderOverlappingSchedule(t *testing.T) {
+	text := renderScheduleText(gridOverlappingClasses(), false)
+	for _, want := range []string{"!CONFLICT", "Conflict on Mon: MATH 5A #100 08:00-09:00 and ENGL 1A #200 08:30-09:30", "Conflict on Mon: ENGL 1A #200 08:30-09:30 and HIST 7B #300 09:00-10:00"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("text grid is missing %q:\n%s", want, text)
+		}
+	}
+
+	page := renderScheduleHTML(gridOverlappingClasses(), "Overlaps")
+	if strings.Count(page, "width: calc(50.00% - 4px)") != 3 || !strings.Contains(page, "left: calc(50.00% + 2px)") {
+		t.Errorf("overlapping bars are not side by side:\n%s", page)
+	}
+
+	svg := renderScheduleSVG(gridOverlappingClasses())
+	if strings.Count(svg, "width=\"51\"") != 3 {
+		t.Errorf("overlapping SVG bars are not half a column wide:\n%s", svg)
+	}
+}
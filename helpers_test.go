@@ -0,0 +1,33 @@
+package main
+
+// Meeting time on the given days from start to end, days are letters as in "MWF" or "TR" (S is Saturday, U Sunday)
+func testMeeting(days string, start Time, end Time) MeetingTime {
+	meetingTime := MeetingTime{StartTime: start, EndTime: end}
+
+	for _, day := range days {
+		switch day {
+		case 'M':
+			meetingTime.Monday = true
+		case 'T':
+			meetingTime.Tuesday = true
+		case 'W':
+			meetingTime.Wednesday = true
+		case 'R':
+			meetingTime.Thursday = true
+		case 'F':
+			meetingTime.Friday = true
+		case 'S':
+			meetingTime.Saturday = true
+		case 'U':
+			meetingTime.Sunday = true
+		}
+	}
+
+	return meetingTime
+}
+
+// Open in person section meeting on Monday for an hour from the given hour
+func testSection(classID string, courseName string, instructor string, hour int) Class {
+	return Class{ClassID: classID, CourseName: courseName, Instructor: instructor, Method: MethodInPerson,
+		Capacity: 30, Enrolled: 10, MeetingTimes: []MeetingTime{testMeeting("M", Time{hour, 0}, Time{hour + 1, 0})}}
+}
@@ -0,0 +1,330 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// iCalendar day names, in meetingDayLetters order
+var icalDayNames = []string{"MO", "TU", "WE", "TH", "FR", "SA", "SU"}
+
+// Weekdays, in meetingDayLetters order
+var meetingWeekdays = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday, time.Sunday}
+
+// Returns the term calendar of the school covering a class date range starting in the given year, false if there is none
+func termForDates(schoolConfig SchoolConfig, date DateRange, year int) (TermCalendar, bool) {
+	start := time.Date(year, time.Month(date.StartMonth), date.StartDay, 0, 0, 0, 0, time.UTC)
+
+	for _, term := range schoolConfig.Terms {
+		termStart := time.Date(term.Year, time.Month(term.Date.StartMonth), term.Date.StartDay, 0, 0, 0, 0, time.UTC)
+		termEnd := dateRangeEnd(term.Date, term.Year)
+		if !start.Before(termStart) && !start.After(termEnd) {
+			return term, true
+		}
+	}
+
+	return TermCalendar{}, false
+}
+
+// Returns the last day of a date range starting in the given year, ranges ending in an earlier month end the next year
+func dateRangeEnd(date DateRange, year int) time.Time {
+	if date.EndMonth < date.StartMonth {
+		year++
+	}
+
+	return time.Date(year, time.Month(date.EndMonth), date.EndDay, 0, 0, 0, 0, time.UTC)
+}
+
+// Returns the year a class date range starts in, the year of the school's term calendar covering it
+// Returns an error if no term calendar covers it, the year has to be given then
+func sectionStartYear(schoolConfig SchoolConfig, date DateRange, today time.Time) (int, error) {
+	year := 0
+	yearEnded := false // the term year was picked from has ended by today
+
+	for _, term := range schoolConfig.Terms {
+		// Terms running into the next year cover dates in an earlier month the year after they start
+		termYear := term.Year
+		if date.StartMonth < term.Date.StartMonth {
+			termYear++
+		}
+		if _, ok := termForDates(schoolConfig, date, termYear); !ok {
+			continue
+		}
+
+		// Several terms can cover the dates (the fall term of every year), pick the one the student is most
+		// likely planning for: the earliest term that has not ended, or the latest one if they all have
+		ended := dateRangeEnd(term.Date, term.Year).Before(today)
+		switch {
+		case year == 0:
+			// First term covering the dates
+		case !ended && (yearEnded || termYear < year):
+			// Has not ended, and either the term picked so far has or this one comes first
+		case ended && yearEnded && termYear > year:
+			// Every term so far has ended, this one ended last
+		default:
+			continue
+		}
+		year, yearEnded = termYear, ended
+	}
+
+	if year == 0 {
+		return 0, fmt.Errorf("no term calendar of school %s covers the dates %d/%d - %d/%d, the year has to be given",
+			schoolConfig.Name, date.StartMonth, date.StartDay, date.EndMonth, date.EndDay)
+	}
+
+	return year, nil
+}
+
+// Escapes a TEXT value (RFC 5545 3.3.11)
+func escapeICalText(text string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(text)
+}
+
+// Folds a content line to lines of at most 75 octets (RFC 5545 3.1), without splitting UTF-8 characters
+func foldICalLine(line string) string {
+	folded := strings.Builder{}
+	length := 0
+
+	for _, r := range line {
+		size := len(string(r))
+		if length+size > 75 {
+			folded.WriteString("\r\n ")
+			length = 1
+		}
+		folded.WriteRune(r)
+		length += size
+	}
+
+	return folded.String()
+}
+
+// Formats a UTC offset in seconds as a UTC-OFFSET, e.g. "-0800"
+func icalUTCOffset(offset int) string {
+	sign := "+"
+	if offset < 0 {
+		sign = "-"
+		offset = -offset
+	}
+
+	return fmt.Sprintf("%s%02d%02d", sign, offset/3600, offset%3600/60)
+}
+
+// Returns the instants in [from, to) the UTC offset of the location changes at, to the minute
+func timezoneTransitions(location *time.Location, from time.Time, to time.Time) []time.Time {
+	transitions := []time.Time{}
+
+	offsetAt := func(instant time.Time) int {
+		_, offset := instant.In(location).Zone()
+		return offset
+	}
+
+	for day := from; day.Before(to); day = day.Add(24 * time.Hour) {
+		next := day.Add(24 * time.Hour)
+		if offsetAt(day) == offsetAt(next) {
+			continue
+		}
+
+		// The first minute with the new offset
+		before, after := day, next
+		for after.Sub(before) > time.Minute {
+			middle := before.Add(after.Sub(before) / 2).Truncate(time.Minute)
+			if offsetAt(middle) == offsetAt(before) {
+				before = middle
+			} else {
+				after = middle
+			}
+		}
+		transitions = append(transitions, after)
+	}
+
+	return transitions
+}
+
+// Builds the VTIMEZONE component of a location for the years from firstYear to lastYear (RFC 5545 3.6.5)
+// The offset in effect on January 1st of firstYear and every change of offset after it are STANDARD or DAYLIGHT observances
+func icalTimezone(location *time.Location, firstYear int, lastYear int) []string {
+	lines := []string{"BEGIN:VTIMEZONE", "TZID:" + location.String()}
+
+	observance := func(instant time.Time, offsetFrom int) {
+		local := instant.In(location)
+		name, offsetTo := local.Zone()
+
+		kind := "STANDARD"
+		if local.IsDST() {
+			kind = "DAYLIGHT"
+		}
+
+		lines = append(lines,
+			"BEGIN:"+kind,
+			// Local time the observance starts at, on the clock of the observance before it
+			"DTSTART:"+instant.Add(time.Duration(offsetFrom)*time.Second).UTC().Format("20060102T150405"),
+			"TZOFFSETFROM:"+icalUTCOffset(offsetFrom),
+			"TZOFFSETTO:"+icalUTCOffset(offsetTo),
+			"TZNAME:"+name,
+			"END:"+kind,
+		)
+	}
+
+	start := time.Date(firstYear, time.January, 1, 0, 0, 0, 0, location)
+	end := time.Date(lastYear+1, time.January, 1, 0, 0, 0, 0, location)
+
+	_, offset := start.Zone()
+	observance(start, offset)
+
+	for _, transition := range timezoneTransitions(location, start, end) {
+		observance(transition, offset)
+		_, offset = transition.In(location).Zone()
+	}
+
+	return append(lines, "END:VTIMEZONE")
+}
+
+// Formats a date and a clock time as a local DATE-TIME
+func icalLocalTime(day time.Time, clockTime Time) string {
+	return fmt.Sprintf("%04d%02d%02dT%02d%02d00", day.Year(), day.Month(), day.Day(), clockTime.Hour, clockTime.Minute)
+}
+
+// Exports the sections of a schedule as an iCalendar, one weekly repeating event per meeting time
+// Meeting times are in the school's timezone, year is the year the sections start in, 0 to find it with sectionStartYear
+// If a term calendar of the school covers the sections, its holidays are left out of the repeating events
+// Returns the calendar and the class ids of the sections that could not be put on it (no meeting times or no dates)
+func exportICalendar(classes []ClassEnhanced, schoolId string, year int) (string, []string, error) {
+	schoolConfig, err := lookupSchool(schoolId)
+	if err != nil {
+		return "", nil, err
+	}
+
+	location, err := time.LoadLocation(schoolConfig.Timezone)
+	if err != nil {
+		return "", nil, fmt.Errorf("unknown timezone %q of school %s", schoolConfig.Timezone, schoolId)
+	}
+
+	header := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//ScheduleGenerator//Schedule//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		"X-WR-CALNAME:" + escapeICalText(schoolConfig.Name+" schedule"),
+		"X-WR-TIMEZONE:" + schoolConfig.Timezone,
+	}
+
+	lines := []string{}
+	skipped := []string{}
+	events := 0
+	now := time.Now()
+	stamp := now.UTC().Format("20060102T150405Z")
+	firstYear, lastYear := 0, 0
+
+	for _, class := range classes {
+		if len(class.MeetingTimes) == 0 || class.Date == (DateRange{}) {
+			skipped = append(skipped, class.ClassID)
+			continue
+		}
+
+		classYear := year
+		if classYear == 0 {
+			if classYear, err = sectionStartYear(schoolConfig, class.Date, now); err != nil {
+				return "", nil, err
+			}
+		}
+
+		firstDay := time.Date(classYear, time.Month(class.Date.StartMonth), class.Date.StartDay, 0, 0, 0, 0, time.UTC)
+		lastDay := dateRangeEnd(class.Date, classYear)
+		term, hasTerm := termForDates(schoolConfig, class.Date, classYear)
+
+		if firstYear == 0 || firstDay.Year() < firstYear {
+			firstYear = firstDay.Year()
+		}
+		if lastDay.Year() > lastYear {
+			lastYear = lastDay.Year()
+		}
+
+		description := []string{
+			"Course: " + class.CourseName,
+			"Class ID: " + class.ClassID,
+			"Instructor: " + class.Instructor,
+			"Instructional method: " + class.InstructionalMethod.Label(),
+		}
+		if class.RequestedCourse != "" {
+			description = append(description, "Taken for: "+class.RequestedCourse)
+		}
+
+		for i, meetingTime := range class.MeetingTimes {
+			days := []string{}
+			meets := map[time.Weekday]bool{}
+			for day, meetsOnDay := range meetingDays(meetingTime) {
+				if meetsOnDay {
+					days = append(days, icalDayNames[day])
+					meets[meetingWeekdays[day]] = true
+				}
+			}
+			if len(days) == 0 {
+				continue
+			}
+
+			// The event starts on the first day of the section it meets on
+			start := firstDay
+			for !meets[start.Weekday()] {
+				start = start.AddDate(0, 0, 1)
+			}
+			if start.After(lastDay) {
+				continue
+			}
+
+			// UNTIL has to be in UTC when DTSTART has a timezone
+			until := time.Date(lastDay.Year(), lastDay.Month(), lastDay.Day(), 23, 59, 59, 0, location).UTC()
+
+			lines = append(lines,
+				"BEGIN:VEVENT",
+				fmt.Sprintf("UID:%s-%d-%04d%02d%02d@schedulegenerator", class.ClassID, i+1, firstDay.Year(), firstDay.Month(), firstDay.Day()),
+				"DTSTAMP:"+stamp,
+				fmt.Sprintf("DTSTART;TZID=%s:%s", schoolConfig.Timezone, icalLocalTime(start, meetingTime.StartTime)),
+				fmt.Sprintf("DTEND;TZID=%s:%s", schoolConfig.Timezone, icalLocalTime(start, meetingTime.EndTime)),
+				fmt.Sprintf("RRULE:FREQ=WEEKLY;BYDAY=%s;UNTIL=%s", strings.Join(days, ","), until.Format("20060102T150405Z")),
+			)
+
+			if hasTerm {
+				excluded := []string{}
+				for _, holiday := range term.Holidays {
+					holidayYear := term.Year
+					if holiday.Month < term.Date.StartMonth {
+						holidayYear++
+					}
+
+					day := time.Date(holidayYear, time.Month(holiday.Month), holiday.Day, 0, 0, 0, 0, time.UTC)
+					if meets[day.Weekday()] && !day.Before(start) && !day.After(lastDay) {
+						excluded = append(excluded, icalLocalTime(day, meetingTime.StartTime))
+					}
+				}
+
+				if len(excluded) > 0 {
+					lines = append(lines, fmt.Sprintf("EXDATE;TZID=%s:%s", schoolConfig.Timezone, strings.Join(excluded, ",")))
+				}
+			}
+
+			events++
+			lines = append(lines,
+				"SUMMARY:"+escapeICalText(class.CourseName),
+				"DESCRIPTION:"+escapeICalText(strings.Join(description, "\n")),
+				"END:VEVENT",
+			)
+		}
+	}
+
+	if events == 0 {
+		return "", skipped, errors.New("no section of the schedule has meeting times and dates")
+	}
+
+	lines = append(append(append(header, icalTimezone(location, firstYear, lastYear)...), lines...), "END:VCALENDAR")
+
+	calendar := strings.Builder{}
+	for _, line := range lines {
+		calendar.WriteString(foldICalLine(line))
+		calendar.WriteString("\r\n")
+	}
+
+	return calendar.String(), skipped, nil
+}
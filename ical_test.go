@@ -0,0 +1,174 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFoldICalLine(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want string
+	}{
+		{"short", "SUMMARY:MATH 5A", "SUMMARY:MATH 5A"},
+		{"exactly 75 octets", strings.Repeat("a", 75), strings.Repeat("a", 75)},
+		{"76 octets", strings.Repeat("a", 76), strings.Repeat("a", 75) + "\r\n a"},
+		{"continuation lines hold 74 octets", strings.Repeat("a", 150), strings.Repeat("a", 75) + "\r\n " + strings.Repeat("a", 74) + "\r\n a"},
+		{"multi-byte character not split", strings.Repeat("a", 74) + "é", strings.Repeat("a", 74) + "\r\n é"},
+		{"multi-byte character that fits", strings.Repeat("a", 73) + "é", strings.Repeat("a", 73) + "é"},
+		{"empty", "", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := foldICalLine(test.line)
+			if got != test.want {
+				t.Errorf("foldICalLine = %q, want %q", got, test.want)
+			}
+
+			for _, line := range strings.Split(got, "\r\n") {
+				if len(line) > 75 {
+					t.Errorf("folded line of %d octets: %q", len(line), line)
+				}
+			}
+		})
+	}
+}
+
+func TestEscapeICalText(t *testing.T) {
+	got := escapeICalText("Course: MATH 5A; Smith, John\nRoom C:\\101")
+	want := `Course: MATH 5A\; Smith\, John\nRoom C:\\101`
+	if got != want {
+		t.Errorf("escapeICalText = %q, want %q", got, want)
+	}
+}
+
+func TestICalUTCOffset(t *testing.T) {
+	tests := map[int]string{
+		-8 * 3600:        "-0800",
+		9 * 3600:         "+0900",
+		0:                "+0000",
+		5*3600 + 1800:    "+0530",
+		-(3*3600 + 1800): "-0330",
+	}
+
+	for offset, want := range tests {
+		if got := icalUTCOffset(offset); got != want {
+			t.Errorf("icalUTCOffset(%d) = %q, want %q", offset, got, want)
+		}
+	}
+}
+
+func TestICalTimezone(t *testing.T) {
+	losAngeles, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Skip("no timezone data:", err)
+	}
+
+	want := []string{
+		"BEGIN:VTIMEZONE",
+		"TZID:America/Los_Angeles",
+		"BEGIN:STANDARD", "DTSTART:20260101T000000", "TZOFFSETFROM:-0800", "TZOFFSETTO:-0800", "TZNAME:PST", "END:STANDARD",
+		"BEGIN:DAYLIGHT", "DTSTART:20260308T020000", "TZOFFSETFROM:-0800", "TZOFFSETTO:-0700", "TZNAME:PDT", "END:DAYLIGHT",
+		"BEGIN:STANDARD", "DTSTART:20261101T020000", "TZOFFSETFROM:-0700", "TZOFFSETTO:-0800", "TZNAME:PST", "END:STANDARD",
+		"END:VTIMEZONE",
+	}
+	if got := icalTimezone(losAngeles, 2026, 2026); !reflect.DeepEqual(got, want) {
+		t.Errorf("icalTimezone(America/Los_Angeles) =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	want = []string{
+		"BEGIN:VTIMEZONE",
+		"TZID:UTC",
+		"BEGIN:STANDARD", "DTSTART:20260101T000000", "TZOFFSETFROM:+0000", "TZOFFSETTO:+0000", "TZNAME:UTC", "END:STANDARD",
+		"END:VTIMEZONE",
+	}
+	if got := icalTimezone(time.UTC, 2026, 2027); !reflect.DeepEqual(got, want) {
+		t.Errorf("icalTimezone(UTC) =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestSectionStartYear(t *testing.T) {
+	fall := DateRange{8, 25, 12, 15}
+	spring := DateRange{2, 9, 6, 5}
+	winter := DateRange{12, 1, 1, 31} // runs into the next year
+
+	withTerms := SchoolConfig{Terms: []TermCalendar{
+		{Name: "Fall 2025", Year: 2025, Date: DateRange{8, 20, 12, 20}},
+		{Name: "Fall 2026", Year: 2026, Date: DateRange{8, 20, 12, 20}},
+		{Name: "Winter 2026", Year: 2026, Date: DateRange{11, 25, 2, 5}},
+		{Name: "Spring 2024", Year: 2024, Date: DateRange{2, 1, 6, 10}},
+	}}
+
+	day := func(year int, month int, dayOfMonth int) time.Time {
+		return time.Date(year, time.Month(month), dayOfMonth, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name   string
+		config SchoolConfig
+		date   DateRange
+		today  time.Time
+		want   int
+	}{
+		{"term running", withTerms, fall, day(2026, 10, 19), 2026},
+		{"term still to come", withTerms, fall, day(2026, 3, 1), 2026},
+		{"every matching term ended", withTerms, fall, day(2028, 3, 1), 2026},
+		{"term into the next year", withTerms, winter, day(2026, 10, 19), 2026},
+		{"only term ended", withTerms, spring, day(2026, 10, 19), 2024},
+	}
+
+	for _, test := range tests {
+		got, err := sectionStartYear(test.config, test.date, test.today)
+		if err != nil || got != test.want {
+			t.Errorf("%s: sectionStartYear = %d, %v, want %d", test.name, got, err, test.want)
+		}
+	}
+
+	for _, config := range []SchoolConfig{{}, withTerms} {
+		if _, err := sectionStartYear(config, DateRange{7, 1, 7, 30}, day(2026, 3, 1)); err == nil {
+			t.Errorf("dates no term covers got a year from %d terms", len(config.Terms))
+		}
+	}
+}
+
+func TestExportICalendarTimezone(t *testing.T) {
+	if _, err := time.LoadLocation(schools["pcc"].Timezone); err != nil {
+		t.Skip("no timezone data:", err)
+	}
+
+	classes := []ClassEnhanced{enhanceClass(Class{
+		ClassID:      "100",
+		CourseName:   "MATH 5A",
+		Instructor:   "John Smith",
+		Date:         DateRange{8, 25, 12, 15},
+		MeetingTimes: []MeetingTime{testMeeting("MW", Time{8, 0}, Time{9, 30})},
+	}, -1)}
+
+	calendar, skipped, err := exportICalendar(classes, "pcc", 2026)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(skipped) != 0 {
+		t.Errorf("skipped = %v, want none", skipped)
+	}
+
+	for _, want := range []string{
+		"BEGIN:VTIMEZONE\r\nTZID:America/Los_Angeles\r\n",
+		"BEGIN:DAYLIGHT\r\nDTSTART:20260308T020000\r\n",
+		"DTSTART;TZID=America/Los_Angeles:20260826T080000\r\n",
+		"DTEND;TZID=America/Los_Angeles:20260826T093000\r\n",
+		"RRULE:FREQ=WEEKLY;BYDAY=MO,WE;UNTIL=20261216T075959Z\r\n",
+	} {
+		if !strings.Contains(calendar, want) {
+			t.Errorf("calendar is missing %q:\n%s", want, calendar)
+		}
+	}
+
+	if strings.Index(calendar, "END:VTIMEZONE") > strings.Index(calendar, "BEGIN:VEVENT") {
+		t.Error("VTIMEZONE comes after the events")
+	}
+}
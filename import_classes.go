@@ -0,0 +1,432 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"schedulegenerator/db"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ImportError is a problem with one row (JSON object or CSV line) of an import
+type ImportError struct {
+	Row     int    `json:"row"` // 1 based, CSV rows count the header
+	ID      string `json:"id"`  // class id or professor id of the row, if it has one
+	Message string `json:"message"`
+}
+
+func (importError ImportError) Error() string {
+	if importError.ID == "" {
+		return fmt.Sprintf("row %d: %s", importError.Row, importError.Message)
+	}
+
+	return fmt.Sprintf("row %d (%s): %s", importError.Row, importError.ID, importError.Message)
+}
+
+// A class read from an import, with the row it started on
+type importedClass struct {
+	row   int
+	class Class
+}
+
+// Reads classes from a JSON export, either a School document or an array of classes
+func parseClassesJSON(data []byte) ([]importedClass, error) {
+	var classes []Class
+
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(data, &classes); err != nil {
+			return nil, err
+		}
+	} else {
+		var school School
+		if err := json.Unmarshal(data, &school); err != nil {
+			return nil, err
+		}
+		classes = school.Classes
+	}
+
+	imported := []importedClass{}
+	for i, class := range classes {
+		imported = append(imported, importedClass{i + 1, class})
+	}
+
+	return imported, nil
+}
+
+// Columns of a CSV export of sections, one meeting time per row
+// Rows with the same classID are the same section, each adds a meeting time
+var classCSVColumns = []string{"courseName", "classID", "instructor", "availability", "instructionalMethod",
+	"days", "startTime", "endTime", "startDate", "endDate", "capacity", "enrolled", "waitlistCapacity", "waitlisted"}
+
+// Reads classes from a CSV export with a header row naming (at least) the courseName, classID and instructionalMethod columns
+// days are letters (M T W R F S U), times are "HH:MM" (24 hour) and dates are "MM/DD"
+// Rows that cannot be parsed are returned as errors, and the other rows of their section are dropped
+// so a section is never imported with only some of its meeting times
+func parseClassesCSV(reader io.Reader) ([]importedClass, []ImportError, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+	csvReader.TrimLeadingSpace = true
+
+	header, err := csvReader.Read()
+	if err != nil {
+		return nil, nil, errors.New("unable to read CSV header")
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		for _, column := range classCSVColumns {
+			if strings.EqualFold(strings.TrimSpace(name), column) {
+				columns[column] = i
+			}
+		}
+	}
+
+	for _, column := range []string{"courseName", "classID", "instructionalMethod"} {
+		if _, ok := columns[column]; !ok {
+			return nil, nil, fmt.Errorf("CSV is missing the %s column", column)
+		}
+	}
+
+	imported := []importedClass{}
+	positions := map[string]int{}
+	failed := map[string]int{} // class id -> row of its first row that could not be parsed
+	importErrors := []ImportError{}
+	row := 1
+
+	for {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		row++
+		if err != nil {
+			importErrors = append(importErrors, ImportError{Row: row, Message: err.Error()})
+			continue
+		}
+
+		value := func(column string) string {
+			i, ok := columns[column]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		class, err := parseClassCSVRecord(value)
+		if err != nil {
+			importErrors = append(importErrors, ImportError{Row: row, ID: value("classID"), Message: err.Error()})
+			if _, ok := failed[value("classID")]; !ok && value("classID") != "" {
+				failed[value("classID")] = row
+			}
+			continue
+		}
+
+		// Another meeting time of a section we already have, the rest of the row must be the same
+		if i, ok := positions[class.ClassID]; ok && class.ClassID != "" {
+			if fields := conflictingSectionFields(imported[i].class, class); len(fields) > 0 {
+				importErrors = append(importErrors, ImportError{Row: row, ID: class.ClassID,
+					Message: fmt.Sprintf("does not match row %d of the same section: %s", imported[i].row, strings.Join(fields, ", "))})
+				if _, ok := failed[class.ClassID]; !ok {
+					failed[class.ClassID] = row
+				}
+				continue
+			}
+			imported[i].class.MeetingTimes = append(imported[i].class.MeetingTimes, class.MeetingTimes...)
+			continue
+		}
+
+		positions[class.ClassID] = len(imported)
+		imported = append(imported, importedClass{row, class})
+	}
+
+	if len(failed) == 0 {
+		return imported, importErrors, nil
+	}
+
+	complete := []importedClass{}
+	for _, importedClass := range imported {
+		if failedRow, ok := failed[importedClass.class.ClassID]; ok {
+			importErrors = append(importErrors, ImportError{Row: importedClass.row, ID: importedClass.class.ClassID,
+				Message: fmt.Sprintf("section not imported, its row %d could not be read", failedRow)})
+			continue
+		}
+		complete = append(complete, importedClass)
+	}
+
+	return complete, importErrors, nil
+}
+
+// Returns the columns, other than the meeting times, that two rows of the same section disagree on
+func conflictingSectionFields(class Class, other Class) []string {
+	fields := []struct {
+		column string
+		same   bool
+	}{
+		{"courseName", class.CourseName == other.CourseName},
+		{"instructor", class.Instructor == other.Instructor},
+		{"availability", class.Availability == other.Availability},
+		{"instructionalMethod", class.InstructionalMethod == other.InstructionalMethod},
+		{"capacity", class.Capacity == other.Capacity},
+		{"enrolled", class.Enrolled == other.Enrolled},
+		{"waitlistCapacity", class.WaitlistCapacity == other.WaitlistCapacity},
+		{"waitlisted", class.Waitlisted == other.Waitlisted},
+		{"startDate and endDate", class.Date == other.Date},
+	}
+
+	conflicting := []string{}
+	for _, field := range fields {
+		if !field.same {
+			conflicting = append(conflicting, field.column)
+		}
+	}
+
+	return conflicting
+}
+
+// Builds a class out of one CSV row
+func parseClassCSVRecord(value func(column string) string) (Class, error) {
+	class := Class{
+		CourseName:          value("courseName"),
+		ClassID:             value("classID"),
+		Instructor:          value("instructor"),
+		Availability:        value("availability"),
+		InstructionalMethod: value("instructionalMethod"),
+		MeetingTimes:        []MeetingTime{},
+	}
+
+	counts := []struct {
+		column string
+		count  *int
+	}{
+		{"capacity", &class.Capacity},
+		{"enrolled", &class.Enrolled},
+		{"waitlistCapacity", &class.WaitlistCapacity},
+		{"waitlisted", &class.Waitlisted},
+	}
+	for _, count := range counts {
+		if value(count.column) == "" {
+			continue
+		}
+		n, err := strconv.Atoi(value(count.column))
+		if err != nil {
+			return Class{}, fmt.Errorf("%s %q is not a number", count.column, value(count.column))
+		}
+		*count.count = n
+	}
+
+	if value("startDate") != "" || value("endDate") != "" {
+		startMonth, startDay, err := parseMonthDay(value("startDate"))
+		if err != nil {
+			return Class{}, err
+		}
+		endMonth, endDay, err := parseMonthDay(value("endDate"))
+		if err != nil {
+			return Class{}, err
+		}
+		class.Date = DateRange{startMonth, startDay, endMonth, endDay}
+	}
+
+	// Sections without days (e.g. asynchronous online) have no meeting times
+	if value("days") == "" {
+		return class, nil
+	}
+
+	meetingTime := MeetingTime{}
+	for _, day := range strings.ToUpper(value("days")) {
+		switch day {
+		case 'M':
+			meetingTime.Monday = true
+		case 'T':
+			meetingTime.Tuesday = true
+		case 'W':
+			meetingTime.Wednesday = true
+		case 'R':
+			meetingTime.Thursday = true
+		case 'F':
+			meetingTime.Friday = true
+		case 'S':
+			meetingTime.Saturday = true
+		case 'U':
+			meetingTime.Sunday = true
+		case ' ', ',':
+		default:
+			return Class{}, fmt.Errorf("unknown day %q in %q", day, value("days"))
+		}
+	}
+
+	var err error
+	if meetingTime.StartTime, err = parseClockTime(value("startTime")); err != nil {
+		return Class{}, err
+	}
+	if meetingTime.EndTime, err = parseClockTime(value("endTime")); err != nil {
+		return Class{}, err
+	}
+
+	class.MeetingTimes = append(class.MeetingTimes, meetingTime)
+
+	return class, nil
+}
+
+// Parses a "HH:MM" time, range is checked by validateClass
+func parseClockTime(value string) (Time, error) {
+	parts := strings.Split(value, ":")
+	if len(parts) != 2 {
+		return Time{}, fmt.Errorf("time %q is not HH:MM", value)
+	}
+
+	hour, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return Time{}, fmt.Errorf("time %q is not HH:MM", value)
+	}
+	minute, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return Time{}, fmt.Errorf("time %q is not HH:MM", value)
+	}
+
+	return Time{hour, minute}, nil
+}
+
+// Parses a "MM/DD" date, range is checked by validateClass
+func parseMonthDay(value string) (int, int, error) {
+	parts := strings.Split(value, "/")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("date %q is not MM/DD", value)
+	}
+
+	month, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("date %q is not MM/DD", value)
+	}
+	day, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, 0, fmt.Errorf("date %q is not MM/DD", value)
+	}
+
+	return month, day, nil
+}
+
+// Returns the problems with a class, empty if there are none
+func validateClass(schoolId string, class Class) []string {
+	problems := []string{}
+
+	if strings.TrimSpace(class.ClassID) == "" {
+		problems = append(problems, "classID is empty")
+	}
+
+	if strings.TrimSpace(class.CourseName) == "" {
+		problems = append(problems, "courseName is empty")
+	}
+
+	if _, ok := parseInstructionalMethod(schoolId, class.InstructionalMethod); !ok {
+		problems = append(problems, fmt.Sprintf("unknown instructional method %q", class.InstructionalMethod))
+	}
+
+	validTime := func(clockTime Time) bool {
+		return clockTime.Hour >= 0 && clockTime.Hour <= 23 && clockTime.Minute >= 0 && clockTime.Minute <= 59
+	}
+
+	for i, meetingTime := range class.MeetingTimes {
+		if !validTime(meetingTime.StartTime) {
+			problems = append(problems, fmt.Sprintf("meeting time %d starts at an invalid time %d:%02d", i+1, meetingTime.StartTime.Hour, meetingTime.StartTime.Minute))
+		}
+		if !validTime(meetingTime.EndTime) {
+			problems = append(problems, fmt.Sprintf("meeting time %d ends at an invalid time %d:%02d", i+1, meetingTime.EndTime.Hour, meetingTime.EndTime.Minute))
+		}
+		if timeInMinutes(meetingTime.EndTime) <= timeInMinutes(meetingTime.StartTime) {
+			problems = append(problems, fmt.Sprintf("meeting time %d does not end after it starts", i+1))
+		}
+		if !meetingTime.Monday && !meetingTime.Tuesday && !meetingTime.Wednesday && !meetingTime.Thursday &&
+			!meetingTime.Friday && !meetingTime.Saturday && !meetingTime.Sunday {
+			problems = append(problems, fmt.Sprintf("meeting time %d has no days", i+1))
+		}
+	}
+
+	validDate := func(month int, day int) bool {
+		return month >= 1 && month <= 12 && day >= 1 && day <= 31
+	}
+	if class.Date != (DateRange{}) {
+		if !validDate(class.Date.StartMonth, class.Date.StartDay) {
+			problems = append(problems, fmt.Sprintf("invalid start date %d/%d", class.Date.StartMonth, class.Date.StartDay))
+		}
+		if !validDate(class.Date.EndMonth, class.Date.EndDay) {
+			problems = append(problems, fmt.Sprintf("invalid end date %d/%d", class.Date.EndMonth, class.Date.EndDay))
+		}
+	}
+
+	if class.Capacity < 0 || class.Enrolled < 0 || class.WaitlistCapacity < 0 || class.Waitlisted < 0 {
+		problems = append(problems, "seat and waitlist counts cannot be negative")
+	}
+
+	return problems
+}
+
+// Validates imported classes, returning the valid ones and an error for every problem found
+func validateClasses(schoolId string, imported []importedClass) ([]Class, []ImportError) {
+	classes := []Class{}
+	importErrors := []ImportError{}
+	seen := map[string]int{}
+
+	for _, importedClass := range imported {
+		class := importedClass.class
+		problems := validateClass(schoolId, class)
+
+		if row, ok := seen[class.ClassID]; ok && class.ClassID != "" {
+			problems = append(problems, fmt.Sprintf("duplicate of row %d", row))
+		}
+		seen[class.ClassID] = importedClass.row
+
+		for _, problem := range problems {
+			importErrors = append(importErrors, ImportError{Row: importedClass.row, ID: class.ClassID, Message: problem})
+		}
+
+		if len(problems) == 0 {
+			classes = append(classes, class)
+		}
+	}
+
+	return classes, importErrors
+}
+
+// Minutes since midnight
+func timeInMinutes(clockTime Time) int {
+	return clockTime.Hour*60 + clockTime.Minute
+}
+
+// Builds a new timestamped School document of the school out of imported classes
+func newSchoolDocument(schoolId string, classes []Class) (School, error) {
+	schoolConfig, err := lookupSchool(schoolId)
+	if err != nil {
+		return School{}, err
+	}
+
+	return School{
+		Timestamp: time.Now().Unix(),
+		School:    schoolConfig.Name,
+		SchoolId:  schoolConfig.ClassDataKey,
+		Classes:   classes,
+	}, nil
+}
+
+// Saves a School document to MongoDB, it becomes the most recent class data of the school
+func saveClassData(school School) error {
+	// Get collection from database
+	collection, err := db.GetDBCollection("Classes")
+
+	if err != nil {
+		fmt.Println(err)
+		return errors.New("unable to fetch collection from database")
+	}
+
+	_, err = collection.InsertOne(context.TODO(), school)
+	if err != nil {
+		return errors.New("unable to save class data")
+	}
+
+	return nil
+}
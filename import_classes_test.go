@@ -0,0 +1,144 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseClassesCSV(t *testing.T) {
+	header := "courseName,classID,instructor,instructionalMethod,days,startTime,endTime\n"
+
+	tests := []struct {
+		name         string
+		csv          string
+		wantSections map[string]int // class id -> number of meeting times
+		wantErrors   []ImportError
+	}{
+		{
+			name: "rows of a section are merged",
+			csv: header +
+				"MATH 5A,100,John Smith,IP,MW,08:00,09:00\n" +
+				"MATH 5A,100,John Smith,IP,F,10:00,11:00\n" +
+				"ENGL 1A,200,Jane Doe,FO,,,\n",
+			wantSections: map[string]int{"100": 2, "200": 0},
+			wantErrors:   []ImportError{},
+		},
+		{
+			name: "bad row after a good row drops the section",
+			csv: header +
+				"MATH 5A,100,John Smith,IP,MW,08:00,09:00\n" +
+				"MATH 5A,100,John Smith,IP,TR,25:00x,26:00\n" +
+				"ENGL 1A,200,Jane Doe,IP,TR,10:00,11:00\n",
+			wantSections: map[string]int{"200": 1},
+			wantErrors: []ImportError{
+				{Row: 3, ID: "100", Message: `time "25:00x" is not HH:MM`},
+				{Row: 2, ID: "100", Message: "section not imported, its row 3 could not be read"},
+			},
+		},
+		{
+			name: "bad row before a good row drops the section",
+			csv: header +
+				"MATH 5A,100,John Smith,IP,MX,08:00,09:00\n" +
+				"MATH 5A,100,John Smith,IP,TR,10:00,11:00\n",
+			wantSections: map[string]int{},
+			wantErrors: []ImportError{
+				{Row: 2, ID: "100", Message: `unknown day 'X' in "MX"`},
+				{Row: 3, ID: "100", Message: "section not imported, its row 2 could not be read"},
+			},
+		},
+		{
+			name: "rows of a section that disagree drop the section",
+			csv: header +
+				"MATH 5A,100,John Smith,IP,MW,08:00,09:00\n" +
+				"MATH 5B,100,Jane Doe,IP,F,10:00,11:00\n" +
+				"ENGL 1A,200,Jane Doe,IP,TR,10:00,11:00\n",
+			wantSections: map[string]int{"200": 1},
+			wantErrors: []ImportError{
+				{Row: 3, ID: "100", Message: "does not match row 2 of the same section: courseName, instructor"},
+				{Row: 2, ID: "100", Message: "section not imported, its row 3 could not be read"},
+			},
+		},
+		{
+			name: "bad only row of a section",
+			csv: header +
+				"MATH 5A,100,John Smith,IP,MW,8,09:00\n" +
+				"ENGL 1A,200,Jane Doe,IP,TR,10:00,11:00\n",
+			wantSections: map[string]int{"200": 1},
+			wantErrors: []ImportError{
+				{Row: 2, ID: "100", Message: `time "8" is not HH:MM`},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			imported, importErrors, err := parseClassesCSV(strings.NewReader(test.csv))
+			if err != nil {
+				t.Fatalf("parseClassesCSV returned %v", err)
+			}
+
+			sections := map[string]int{}
+			for _, importedClass := range imported {
+				sections[importedClass.class.ClassID] = len(importedClass.class.MeetingTimes)
+			}
+			if len(sections) != len(test.wantSections) {
+				t.Errorf("got sections %v, want %v", sections, test.wantSections)
+			}
+			for classID, meetingTimes := range test.wantSections {
+				if got, ok := sections[classID]; !ok || got != meetingTimes {
+					t.Errorf("got sections %v, want %v", sections, test.wantSections)
+				}
+			}
+
+			if len(importErrors) != len(test.wantErrors) {
+				t.Fatalf("got errors %v, want %v", importErrors, test.wantErrors)
+			}
+			for i := range importErrors {
+				if importErrors[i] != test.wantErrors[i] {
+					t.Errorf("error %d = %v, want %v", i, importErrors[i], test.wantErrors[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseClassesCSVMissingColumn(t *testing.T) {
+	_, _, err := parseClassesCSV(strings.NewReader("courseName,instructionalMethod\nMATH 5A,IP\n"))
+	if err == nil {
+		t.Error("parseClassesCSV accepted a CSV without a classID column")
+	}
+}
+
+func TestValidateClass(t *testing.T) {
+	valid := Class{
+		CourseName:          "MATH 5A",
+		ClassID:             "100",
+		InstructionalMethod: "IP",
+		MeetingTimes:        []MeetingTime{testMeeting("M", Time{8, 0}, Time{9, 0})},
+	}
+
+	tests := []struct {
+		name   string
+		change func(class *Class)
+		want   int // number of problems
+	}{
+		{"valid", func(class *Class) {}, 0},
+		{"unknown method", func(class *Class) { class.InstructionalMethod = "XX" }, 1},
+		{"hour out of range", func(class *Class) { class.MeetingTimes[0].EndTime = Time{25, 0} }, 1},
+		{"ends before it starts", func(class *Class) { class.MeetingTimes[0].EndTime = Time{7, 0} }, 1},
+		{"no days", func(class *Class) { class.MeetingTimes[0].Monday = false }, 1},
+		{"invalid date", func(class *Class) { class.Date = DateRange{13, 1, 5, 30} }, 1},
+		{"negative seats", func(class *Class) { class.Enrolled = -1 }, 1},
+		{"no id or name", func(class *Class) { class.ClassID = ""; class.CourseName = " " }, 2},
+	}
+
+	for _, test := range tests {
+		class := valid
+		class.MeetingTimes = append([]MeetingTime{}, valid.MeetingTimes...)
+		test.change(&class)
+
+		if problems := validateClass("pcc", class); len(problems) != test.want {
+			t.Errorf("%s: validateClass = %v, want %d problems", test.name, problems, test.want)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"schedulegenerator/db"
+	"strings"
+	"time"
+)
+
+// Reads professors from a JSON export, either a ProfessorExport document or an array of professors
+func parseProfessorsJSON(data []byte) ([]ProfessorType, error) {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		var professors []ProfessorType
+		if err := json.Unmarshal(data, &professors); err != nil {
+			return nil, err
+		}
+		return professors, nil
+	}
+
+	var export ProfessorExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, err
+	}
+
+	return export.Professors, nil
+}
+
+// Trims and collapses the whitespace of every name of a professor
+// A middle name left in the first name ("John Q") is moved to the middle name
+func normalizeProfessorNames(professor ProfessorType) ProfessorType {
+	collapse := func(s string) string {
+		return strings.Join(strings.Fields(s), " ")
+	}
+
+	professor.FirstName = collapse(professor.FirstName)
+	professor.MiddleName = collapse(professor.MiddleName)
+	professor.LastName = collapse(professor.LastName)
+	professor.Department = collapse(professor.Department)
+
+	if professor.MiddleName == "" {
+		if space := strings.Index(professor.FirstName, " "); space != -1 {
+			professor.MiddleName = professor.FirstName[space+1:]
+			professor.FirstName = professor.FirstName[:space]
+		}
+	}
+
+	return professor
+}
+
+// Cleans imported professors: normalizes their names, drops invalid professors and merges professors with the same id
+// Returns the clean professors, an error for every professor dropped, and a warning for every likely duplicate kept
+// (same name and department under different ids)
+func cleanProfessors(professors []ProfessorType) ([]ProfessorType, []ImportError, []ImportError) {
+	cleaned := []ProfessorType{}
+	importErrors := []ImportError{}
+	warnings := []ImportError{}
+	positions := map[int]int{}
+	rows := map[int]int{}
+	names := map[string]int{}
+
+	for i, professor := range professors {
+		row := i + 1
+		professor = normalizeProfessorNames(professor)
+		id := fmt.Sprint(professor.Id)
+
+		problems := []string{}
+		if professor.Id == 0 {
+			problems = append(problems, "id is missing")
+		}
+		if professor.LastName == "" {
+			problems = append(problems, "lastName is empty")
+		}
+		if professor.OverallRating != -1 && (professor.OverallRating < 0 || professor.OverallRating > 5) {
+			problems = append(problems, fmt.Sprintf("overallRating %.1f is not between 0 and 5", professor.OverallRating))
+		}
+		if professor.TotalRatings < 0 {
+			problems = append(problems, "totalRatings cannot be negative")
+		}
+		if len(problems) > 0 {
+			for _, problem := range problems {
+				importErrors = append(importErrors, ImportError{Row: row, ID: id, Message: problem})
+			}
+			continue
+		}
+
+		// The same professor twice, keep whichever has a rating and the most ratings
+		if position, ok := positions[professor.Id]; ok {
+			importErrors = append(importErrors, ImportError{Row: row, ID: id, Message: fmt.Sprintf("duplicate id of row %d, keeping the one with more ratings", rows[professor.Id])})
+			_, rated := professorRating(professor)
+			_, keptRated := professorRating(cleaned[position])
+			if (rated && !keptRated) || (rated == keptRated && professor.TotalRatings > cleaned[position].TotalRatings) {
+				cleaned[position] = professor
+			}
+			continue
+		}
+
+		// Rate my professor often has several profiles for the same person
+		key := normalizeName(professorFullName(professor)) + "|" + normalizeName(professor.Department)
+		if otherRow, ok := names[key]; ok {
+			warnings = append(warnings, ImportError{Row: row, ID: id, Message: fmt.Sprintf("same name and department as row %d", otherRow)})
+		} else {
+			names[key] = row
+		}
+
+		positions[professor.Id] = len(cleaned)
+		rows[professor.Id] = row
+		cleaned = append(cleaned, professor)
+	}
+
+	return cleaned, importErrors, warnings
+}
+
+// Builds the next version of a school's ProfessorExport out of imported professors
+func newProfessorExport(schoolId string, professors []ProfessorType) (ProfessorExport, error) {
+	schoolConfig, err := lookupSchool(schoolId)
+	if err != nil {
+		return ProfessorExport{}, err
+	}
+
+	version := 1
+	if previous, err := fetchProfessorData(schoolId); err == nil {
+		version = previous.Version + 1
+	}
+
+	for i := range professors {
+		professors[i].SchoolID = schoolConfig.RatingSourceID
+	}
+
+	return ProfessorExport{
+		Timestamp:  time.Now().Unix(),
+		SchoolId:   schoolConfig.RatingSourceID,
+		Version:    version,
+		Professors: professors,
+	}, nil
+}
+
+// Saves a ProfessorExport to MongoDB, it becomes the most recent professor data of the school
+func saveProfessorData(export ProfessorExport) error {
+	// Get collection from database
+	collection, err := db.GetDBCollection("Professors")
+
+	if err != nil {
+		fmt.Println(err)
+		return errors.New("unable to fetch collection from database")
+	}
+
+	_, err = collection.InsertOne(context.TODO(), export)
+	if err != nil {
+		return errors.New("unable to save professor data")
+	}
+
+	return nil
+}
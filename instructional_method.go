@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"os"
+	"sort"
+	"strings"
+)
+
+// InstructionalMethod is how a section is taught, independent of the codes a school uses in its schedule data
+type InstructionalMethod int
+
+const (
+	MethodUnknown InstructionalMethod = iota
+	MethodInPerson
+	MethodHybrid
+	MethodOnline
+)
+
+// Names used by the API (JSON) for each method
+var instructionalMethodNames = map[InstructionalMethod]string{
+	MethodUnknown:  "unknown",
+	MethodInPerson: "inPerson",
+	MethodHybrid:   "hybrid",
+	MethodOnline:   "online",
+}
+
+// Human readable labels for each method
+var instructionalMethodLabels = map[InstructionalMethod]string{
+	MethodUnknown:  "Unknown",
+	MethodInPerson: "In Person",
+	MethodHybrid:   "Hybrid",
+	MethodOnline:   "Fully Online",
+}
+
+func (method InstructionalMethod) String() string {
+	if name, ok := instructionalMethodNames[method]; ok {
+		return name
+	}
+
+	return instructionalMethodNames[MethodUnknown]
+}
+
+// Label returns a human readable name for the method
+func (method InstructionalMethod) Label() string {
+	if label, ok := instructionalMethodLabels[method]; ok {
+		return label
+	}
+
+	return instructionalMethodLabels[MethodUnknown]
+}
+
+func (method InstructionalMethod) MarshalText() ([]byte, error) {
+	return []byte(method.String()), nil
+}
+
+func (method *InstructionalMethod) UnmarshalText(text []byte) error {
+	for m, name := range instructionalMethodNames {
+		if strings.EqualFold(name, string(text)) {
+			*method = m
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown instructional method %q", string(text))
+}
+
+func (method InstructionalMethod) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	text, _ := method.MarshalText()
+	return marshalTextBSONValue(text)
+}
+
+func (method *InstructionalMethod) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	return unmarshalTextBSONValue(t, data, method.UnmarshalText, func(number int64) {
+		*method = InstructionalMethod(number)
+	})
+}
+
+// Converts a school specific instructional method code into an InstructionalMethod
+// Returns false if the code is not known for that school
+func parseInstructionalMethod(schoolId string, code string) (InstructionalMethod, bool) {
+	method, ok := schools[schoolId].InstructionalMethodCodes[strings.ToUpper(strings.TrimSpace(code))]
+	if !ok {
+		return MethodUnknown, false
+	}
+
+	return method, true
+}
+
+// Resolves the raw instructional method code of every class in the school document
+// Returns the codes that could not be mapped so they can be reported
+func normalizeInstructionalMethods(school *School, schoolId string) []string {
+	unknownCodes := map[string]bool{}
+
+	for i, class := range school.Classes {
+		method, ok := parseInstructionalMethod(schoolId, class.InstructionalMethod)
+		if !ok {
+			unknownCodes[class.InstructionalMethod] = true
+		}
+		school.Classes[i].Method = method
+	}
+
+	codes := []string{}
+	for code := range unknownCodes {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	return codes
+}
+
+// Warns about the instructional method codes normalizeInstructionalMethods could not map
+func warnUnknownInstructionalMethods(unknownCodes []string) {
+	if len(unknownCodes) > 0 {
+		fmt.Fprintln(os.Stderr, "Unknown instructional method codes: "+strings.Join(unknownCodes, ", "))
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestParseInstructionalMethod(t *testing.T) {
+	tests := []struct {
+		schoolId string
+		code     string
+		want     InstructionalMethod
+		wantOk   bool
+	}{
+		{"pcc", "IP", MethodInPerson, true},
+		{"pcc", "HY", MethodHybrid, true},
+		{"pcc", "FO", MethodOnline, true},
+		{"pcc", " fo ", MethodOnline, true},
+		{"pcc", "XX", MethodUnknown, false},
+		{"pcc", "", MethodUnknown, false},
+		{"other", "IP", MethodUnknown, false},
+	}
+
+	for _, test := range tests {
+		got, ok := parseInstructionalMethod(test.schoolId, test.code)
+		if got != test.want || ok != test.wantOk {
+			t.Errorf("parseInstructionalMethod(%q, %q) = %v, %v, want %v, %v", test.schoolId, test.code, got, ok, test.want, test.wantOk)
+		}
+	}
+}
+
+func TestNormalizeInstructionalMethods(t *testing.T) {
+	school := School{Classes: []Class{
+		{ClassID: "100", InstructionalMethod: "IP"},
+		{ClassID: "200", InstructionalMethod: "ZZ"},
+		{ClassID: "300", InstructionalMethod: "FO"},
+		{ClassID: "400", InstructionalMethod: "AA"},
+		{ClassID: "500", InstructionalMethod: "ZZ"},
+	}}
+
+	unknownCodes := normalizeInstructionalMethods(&school, "pcc")
+	if want := []string{"AA", "ZZ"}; !reflect.DeepEqual(unknownCodes, want) {
+		t.Errorf("unknown codes = %v, want %v", unknownCodes, want)
+	}
+
+	want := []InstructionalMethod{MethodInPerson, MethodUnknown, MethodOnline, MethodUnknown, MethodUnknown}
+	for i, class := range school.Classes {
+		if class.Method != want[i] {
+			t.Errorf("section %s method = %v, want %v", class.ClassID, class.Method, want[i])
+		}
+	}
+}
+
+func TestInstructionalMethodJSON(t *testing.T) {
+	data, err := json.Marshal([]InstructionalMethod{MethodInPerson, MethodHybrid, MethodOnline})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `["inPerson","hybrid","online"]` {
+		t.Errorf("encoded as %s", data)
+	}
+
+	var methods []InstructionalMethod
+	if err := json.Unmarshal([]byte(`["ONLINE","inperson"]`), &methods); err != nil {
+		t.Fatal(err)
+	}
+	if want := []InstructionalMethod{MethodOnline, MethodInPerson}; !reflect.DeepEqual(methods, want) {
+		t.Errorf("decoded %v, want %v", methods, want)
+	}
+
+	if err := json.Unmarshal([]byte(`["HY"]`), &methods); err == nil {
+		t.Error("a raw school code was accepted as an instructional method")
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+// Minimum confidence for two instructor names to be considered the same person
+const instructorMatchThreshold = 0.90
+
+type InstructorConstraint struct {
+	CourseName string   `json:"courseName"` // course this applies to, empty applies to every course
+	Include    []string `json:"include"`    // if not empty, only sections taught by one of these instructors are kept
+	Exclude    []string `json:"exclude"`    // sections taught by any of these instructors are removed
+}
+
+type InstructorPreference struct {
+	CourseName string  `json:"courseName"` // course this applies to, empty applies to every course
+	Instructor string  `json:"instructor"`
+	Weight     float32 `json:"weight"` // added to the score of sections taught by the instructor, negative to avoid them
+}
+
+// Compares two instructor names the same way ratings are matched to classes
+func instructorNamesMatch(name1 string, name2 string) bool {
+	return compareNames(name1, parseInstructorName(name2)) > instructorMatchThreshold
+}
+
+// Returns true if the instructor matches any of the names
+func instructorInList(instructor string, names []string) bool {
+	for _, name := range names {
+		if instructorNamesMatch(instructor, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Checks the must-have and must-avoid instructor lists against a class
+func fitsInstructorConstraints(class Class, constraints UserScheduleConstraints) bool {
+	for _, instructorConstraint := range constraints.Instructors {
+		if !courseConstraintApplies(instructorConstraint.CourseName, class.CourseName, class.requestedCourseCode()) {
+			continue
+		}
+
+		if len(instructorConstraint.Include) > 0 && !instructorInList(class.Instructor, instructorConstraint.Include) {
+			return false
+		}
+
+		if instructorInList(class.Instructor, instructorConstraint.Exclude) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Adds up the preference weights that apply to the instructor of a class
+// Returns the positive weights (instructors asked for) and the negative weights (instructors to avoid) separately
+func instructorPreferenceWeights(class ClassEnhanced, constraints UserScheduleConstraints) (float32, float32) {
+	var bonus, penalty float32
+
+	for _, preference := range constraints.InstructorPreferences {
+		if !courseConstraintApplies(preference.CourseName, class.CourseName, class.requestedCourseCode()) {
+			continue
+		}
+
+		if !instructorNamesMatch(class.Instructor, preference.Instructor) {
+			continue
+		}
+
+		if preference.Weight < 0 {
+			penalty += preference.Weight
+		} else {
+			bonus += preference.Weight
+		}
+	}
+
+	return bonus, penalty
+}
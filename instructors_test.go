@@ -0,0 +1,63 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestFitsInstructorConstraints(t *testing.T) {
+	math := Class{ClassID: "100", CourseName: "MATH 5A", Instructor: "John Smith"}
+	english := Class{ClassID: "200", CourseName: "ENGL 1A", Instructor: "Jane Doe"}
+
+	tests := []struct {
+		name        string
+		constraints []InstructorConstraint
+		want        map[string]bool // class id -> kept
+	}{
+		{"no constraints", nil, map[string]bool{"100": true, "200": true}},
+		{"include", []InstructorConstraint{{Include: []string{"John Smith"}}}, map[string]bool{"100": true, "200": false}},
+		{"include one of", []InstructorConstraint{{Include: []string{"Ann Lee", "Jane Doe"}}}, map[string]bool{"100": false, "200": true}},
+		{"exclude", []InstructorConstraint{{Exclude: []string{"Jane Doe"}}}, map[string]bool{"100": true, "200": false}},
+		{"include for another course", []InstructorConstraint{{CourseName: "ENGL 1A", Include: []string{"Ann Lee"}}}, map[string]bool{"100": true, "200": false}},
+		{"exclude for another course", []InstructorConstraint{{CourseName: "ENGL 1A", Exclude: []string{"John Smith"}}}, map[string]bool{"100": true, "200": true}},
+		{"included and excluded", []InstructorConstraint{{Include: []string{"John Smith"}}, {Exclude: []string{"John Smith"}}}, map[string]bool{"100": false, "200": false}},
+	}
+
+	for _, test := range tests {
+		constraints := UserScheduleConstraints{Instructors: test.constraints}
+		for _, class := range []Class{math, english} {
+			if got := fitsInstructorConstraints(class, constraints); got != test.want[class.ClassID] {
+				t.Errorf("%s: section %s kept = %v, want %v", test.name, class.ClassID, got, test.want[class.ClassID])
+			}
+		}
+	}
+}
+
+func TestInstructorPreferenceWeights(t *testing.T) {
+	class := ClassEnhanced{ClassID: "100", CourseName: "MATH 5A", Instructor: "John Smith"}
+
+	tests := []struct {
+		name        string
+		preferences []InstructorPreference
+		wantBonus   float32
+		wantPenalty float32
+	}{
+		{"no preferences", nil, 0, 0},
+		{"preferred", []InstructorPreference{{Instructor: "John Smith", Weight: 1.5}}, 1.5, 0},
+		{"avoided", []InstructorPreference{{Instructor: "John Smith", Weight: -2}}, 0, -2},
+		{"other instructor", []InstructorPreference{{Instructor: "Jane Doe", Weight: 3}}, 0, 0},
+		{"other course", []InstructorPreference{{CourseName: "ENGL 1A", Instructor: "John Smith", Weight: 3}}, 0, 0},
+		{"same course", []InstructorPreference{{CourseName: "MATH 5A", Instructor: "John Smith", Weight: 3}}, 3, 0},
+		{"added up", []InstructorPreference{
+			{Instructor: "John Smith", Weight: 1},
+			{CourseName: "MATH 5A", Instructor: "John Smith", Weight: 0.5},
+			{Instructor: "John Smith", Weight: -0.25},
+		}, 1.5, -0.25},
+	}
+
+	for _, test := range tests {
+		bonus, penalty := instructorPreferenceWeights(class, UserScheduleConstraints{InstructorPreferences: test.preferences})
+		if bonus != test.wantBonus || penalty != test.wantPenalty {
+			t.Errorf("%s: instructorPreferenceWeights = %v, %v, want %v, %v", test.name, bonus, penalty, test.wantBonus, test.wantPenalty)
+		}
+	}
+}
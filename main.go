@@ -6,14 +6,12 @@ import (
 	"fmt"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo/options"
-	"main/db"
+	"os"
+	"schedulegenerator/db"
 	"sort"
 	"strconv"
-	"strings"
 )
 
-const SCHOOL_ID string = "2649" // PCC school id FROM rate my professor
-
 type Time struct {
 	Hour   int `json:"Hour"`
 	Minute int `json:"Minute"`
@@ -51,43 +49,76 @@ type Class struct {
 	InstructionalMethod string        `json:"instructionalMethod"`
 	MeetingTimes        []MeetingTime `json:"meetingTimes"`
 	Date                DateRange     `json:"date"`
+	Capacity            int           `json:"capacity"`
+	Enrolled            int           `json:"enrolled"`
+	WaitlistCapacity    int           `json:"waitlistCapacity"`
+	Waitlisted          int           `json:"waitlisted"`
+
+	// Resolved from InstructionalMethod using the school's codes, not stored in the database
+	Method InstructionalMethod `json:"method" bson:"-"`
+	// Requested course this class satisfies when it was picked as an equivalent of it, not stored in the database
+	RequestedCourse string `json:"requestedCourse,omitempty" bson:"-"`
 }
 
 type ClassEnhanced struct {
-	CourseName          string        `json:"courseName"`
-	ClassID             string        `json:"classID"`
-	Instructor          string        `json:"instructor"`
-	InstructorRating    float32       `json:"instructorRating"`
-	Availability        string        `json:"availability"`
-	InstructionalMethod string        `json:"instructionalMethod"`
-	MeetingTimes        []MeetingTime `json:"meetingTimes"`
-	Date                DateRange     `json:"date"`
+	CourseName          string              `json:"courseName"`
+	RequestedCourse     string              `json:"requestedCourse,omitempty"` // course asked for when CourseName is a cross-listed or equivalent course
+	ClassID             string              `json:"classID"`
+	Instructor          string              `json:"instructor"`
+	InstructorRating    float32             `json:"instructorRating"`
+	Rated               bool                `json:"rated"`           // false when InstructorRating comes from the UnratedPolicy
+	ProfessorID         int                 `json:"professorId"`     // rate my professor id of the matched instructor, 0 if none
+	MatchConfidence     float64             `json:"matchConfidence"` // how confident we are the instructor is that professor (0 - 1)
+	RatingCount         int                 `json:"ratingCount"`     // number of ratings behind InstructorRating
+	WeightedRating      float32             `json:"weightedRating"`  // InstructorRating pulled towards the school average when there are few ratings
+	Difficulty          float32             `json:"difficulty"`      // 1 - 5, 0 if unknown
+	WouldTakeAgain      float32             `json:"wouldTakeAgain"`  // percent of students that would take the professor again, -1 if unknown
+	Availability        AvailabilityStatus  `json:"availability"`
+	InstructionalMethod InstructionalMethod `json:"instructionalMethod"`
+	MeetingTimes        []MeetingTime       `json:"meetingTimes"`
+	Date                DateRange           `json:"date"`
+	Capacity            int                 `json:"capacity"`
+	Enrolled            int                 `json:"enrolled"`
+	WaitlistCapacity    int                 `json:"waitlistCapacity"`
+	Waitlisted          int                 `json:"waitlisted"`
+}
+
+// Schedule is one section of every requested course, with the score it was ranked by
+type Schedule struct {
+	Classes   []ClassEnhanced   `json:"classes"`
+	Rating    float32           `json:"rating"`
+	Breakdown ScheduleBreakdown `json:"breakdown"` // why the schedule got its rating
 }
 
 type School struct {
 	Timestamp int64   `json:"timestamp"`
 	School    string  `json:"school"`
+	SchoolId  string  `json:"schoolId"` // class data key of the school, see SchoolConfig
 	Classes   []Class `json:"classes"`
 }
 
 type ProfessorType struct {
-	Department      string `json:"department"`
-	SchoolID        string `json:"schoolId"`
-	InstitutionName string `json:"institutionName"`
-	FirstName       string `json:"firstName"`
-	MiddleName      string `json:"middleName"`
-	LastName        string `json:"lastName"`
-	Id              int    `json:"id"`
-	TotalRatings    int    `json:"totalRatings"`
-	RatingsClass    string `json:"ratingsClass"`
-	ContentType     string `json:"contentType"`
-	CategoryType    string `json:"categoryType"`
-	OverallRating   string `json:"overallRating"`
+	Department      string        `json:"department"`
+	SchoolID        string        `json:"schoolId"`
+	InstitutionName string        `json:"institutionName"`
+	FirstName       string        `json:"firstName"`
+	MiddleName      string        `json:"middleName"`
+	LastName        string        `json:"lastName"`
+	Id              int           `json:"id"`
+	TotalRatings    int           `json:"totalRatings"`
+	RatingsClass    string        `json:"ratingsClass"`
+	ContentType     string        `json:"contentType"`
+	CategoryType    string        `json:"categoryType"`
+	OverallRating   NumericRating `json:"overallRating"` // -1 if none
+
+	AvgDifficulty         float32  `json:"avgDifficulty"`         // 1 - 5, 0 if unknown
+	WouldTakeAgainPercent *float32 `json:"wouldTakeAgainPercent"` // 0 - 100, nil if unknown
 }
 
 type ProfessorExport struct {
 	Timestamp  int64           `json:"timestamp"`
 	SchoolId   string          `json:"schoolId"`
+	Version    int             `json:"version"` // increases with every import of the school's professors, 0 for exports that were not imported
 	Professors []ProfessorType `json:"professors"`
 }
 
@@ -95,6 +126,8 @@ type UserScheduleConstraints struct {
 	SchoolId string   `json:"schoolId"`
 	Courses  []string `json:"courses"`
 
+	CourseEquivalents [][]string `json:"courseEquivalents"` // groups of courses that can satisfy each other, on top of the school's
+
 	MondayTime    []TimeRange `json:"mondayTime"`
 	TuesdayTime   []TimeRange `json:"tuesdayTime"`
 	WednesdayTime []TimeRange `json:"wednesdayTime"`
@@ -103,15 +136,43 @@ type UserScheduleConstraints struct {
 	SaturdayTime  []TimeRange `json:"saturdayTime"`
 	SundayTime    []TimeRange `json:"sundayTime"`
 
-	InstructionalMethods []string `json:"instructionalMethods"`
-	Availability         []string `json:"availability"`
+	InstructionalMethods []InstructionalMethod `json:"instructionalMethods"`
+	Availability         []AvailabilityStatus  `json:"availability"`
+
+	MaxWaitlisted   *int    `json:"maxWaitlisted"`   // sections with more students on the waitlist are removed (nil = no limit)
+	MinOpenSeats    int     `json:"minOpenSeats"`    // sections with fewer open seats are removed
+	OpenSeatsWeight float32 `json:"openSeatsWeight"` // how much sections with more open seats are preferred when scoring
+
+	Instructors           []InstructorConstraint `json:"instructors"`
+	InstructorPreferences []InstructorPreference `json:"instructorPreferences"`
+
+	LockedClassIDs []string `json:"lockedClassIDs"` // sections that must appear in every schedule
+	BannedClassIDs []string `json:"bannedClassIDs"` // sections that must never appear
+
+	UnratedPolicy UnratedPolicy `json:"unratedPolicy"` // rating given to sections whose instructor has no rating
+	MinRating     float32       `json:"minRating"`     // sections rated lower are removed before generating schedules
+	RatingMode    RatingMode    `json:"ratingMode"`    // which rating is used for MinRating and scoring
+
+	SelectionMode        SelectionMode `json:"selectionMode"`        // how the schedules shown are picked from the ranked schedules
+	MinDifferentSections int           `json:"minDifferentSections"` // diverse selection: sections each schedule must differ by, unless its days differ (default 2)
+	DiversityTradeoff    *float32      `json:"diversityTradeoff"`    // mmr selection: 1 picks by rating only, 0 by difference only (nil = 0.7)
 }
 
 func main() {
+	// Run a command instead of the test data if one was given
+	if len(os.Args) > 1 {
+		err := runCommand(os.Args[1], os.Args[2:])
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// given a list of courses and conditions (THIS IS TEST DATA)
 	courses := []string{"MATH 008", "MATH 003", "PHIL 025", "SOC 001", "MATH 005A", "CHEM 001A"}
-	instructionalMethods := []string{"HY", "FO", "IP"}
-	availability := []string{"open", "waitlisted", "closed"}
+	instructionalMethods := []InstructionalMethod{MethodHybrid, MethodOnline, MethodInPerson}
+	availability := []AvailabilityStatus{AvailabilityOpen, AvailabilityWaitlisted, AvailabilityClosed}
 
 	startTime := Time{8, 30}
 	endTime := Time{12, 0}
@@ -124,7 +185,7 @@ func main() {
 	mondayTime := []TimeRange{timeRange, timeRange1}
 
 	// This is the algorithms INPUT (TEST DATA)
-	userScheduleConstraints := UserScheduleConstraints{SchoolId: SCHOOL_ID, Courses: courses, InstructionalMethods: instructionalMethods, Availability: availability,
+	userScheduleConstraints := UserScheduleConstraints{SchoolId: "pcc", Courses: courses, InstructionalMethods: instructionalMethods, Availability: availability,
 		MondayTime: mondayTime, TuesdayTime: mondayTime, WednesdayTime: mondayTime, ThursdayTime: mondayTime}
 
 	// pull schedule data from db
@@ -134,23 +195,19 @@ func main() {
 		return
 	}
 
-	// List of all courses specified in "courses" constraint
-	classes := filterCourses(school, userScheduleConstraints)
+	// Report instructional method codes we do not know how to map for this school
+	warnUnknownInstructionalMethods(normalizeInstructionalMethods(&school, userScheduleConstraints.SchoolId))
 
-	// Remove courses that do not fit schedule / instructionalMethods / availability
-	classes = getClassesThatFitScheduleConstraints(classes, userScheduleConstraints)
+	// Report locked sections that cannot be satisfied and suggest course codes for the courses that were not found
+	reportConstraintProblems(os.Stdout, school, userScheduleConstraints)
 
-	// Fetch professor rating from database
-	professorsExport, err := fetchProfessorData(SCHOOL_ID)
+	// Sections that fit the constraints, with their instructors' ratings
+	enhancedClasses, err := prepareClasses(school, userScheduleConstraints)
 	if err != nil {
 		fmt.Println(err)
 		return
 	}
 
-	// Integrate rate my professor ratings into classes data
-	enhancedClasses := []ClassEnhanced{}
-	enhancedClasses = integrateRatingsIntoClassData(classes, professorsExport.Professors)
-
 	// USEFUL REPORTING INFO
 	//for _, class := range enhancedClasses {
 	//	fmt.Print("Class ID: " + class.ClassID)
@@ -161,14 +218,65 @@ func main() {
 	//}
 
 	// algorithm
-	resultClasses := []ClassEnhanced{}
-	resultClasses = generateSchedule(enhancedClasses)
+	schedules := generateSchedule(enhancedClasses, userScheduleConstraints)
 
 	// output result -> return to sender
-	fmt.Println(len(resultClasses))
+	fmt.Println("Number of possible schedules: " + strconv.Itoa(len(schedules)))
+
+	// Prints the ten best schedules (or the ten picked by the selection mode) - just printing it out for testing
+	printSchedules(breakdownSchedules(selectSchedules(schedules, userScheduleConstraints, 10), userScheduleConstraints))
 }
 
-func generateSchedule(classes []ClassEnhanced) []ClassEnhanced {
+// Filters the school's classes down to the sections that can be scheduled with the constraints and rates their instructors
+// The school's instructional methods must already be resolved (see normalizeInstructionalMethods)
+func prepareClasses(school School, userScheduleConstraints UserScheduleConstraints) ([]ClassEnhanced, error) {
+	// List of all courses specified in "courses" constraint (and courses of locked sections)
+	classes := filterCourses(school, userScheduleConstraints)
+
+	// Remove courses that do not fit schedule / instructionalMethods / availability / instructors
+	classes = getClassesThatFitScheduleConstraints(classes, userScheduleConstraints)
+
+	// Fetch professor rating from database
+	professorsExport, err := fetchProfessorData(userScheduleConstraints.SchoolId)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fetch the instructor aliases staff have curated
+	aliases, err := fetchInstructorAliases(userScheduleConstraints.SchoolId)
+	if err != nil {
+		return nil, err
+	}
+
+	// Integrate rate my professor ratings into classes data, rate the unrated instructors and remove the sections below the minimum rating
+	return rateClasses(userScheduleConstraints.SchoolId, classes, professorsExport, aliases, userScheduleConstraints), nil
+}
+
+// Prints schedules with their rank and rating breakdown
+func printSchedules(schedules []Schedule) {
+	for i := range schedules {
+		fmt.Println("------------------")
+		fmt.Println("Schedule #" + strconv.Itoa(i+1))
+		fmt.Println("Rating: ")
+		fmt.Println(schedules[i].Rating)
+		breakdown := schedules[i].Breakdown
+		fmt.Printf("Ratings %.2f + open seats %.2f + preferred instructors %.2f + penalties %.2f\n",
+			breakdown.Rating, breakdown.OpenSeats, breakdown.InstructorPreference, breakdown.Penalties)
+		fmt.Printf("%d days on campus, %d idle minutes, %02d:%02d - %02d:%02d\n", breakdown.DaysOnCampus, breakdown.IdleMinutes,
+			breakdown.EarliestStart.Hour, breakdown.EarliestStart.Minute, breakdown.LatestEnd.Hour, breakdown.LatestEnd.Minute)
+		for _, class := range schedules[i].Classes {
+			if class.RequestedCourse != "" {
+				// Picked as an equivalent of the course that was asked for
+				fmt.Println(class.ClassID + " (" + class.CourseName + " for " + class.RequestedCourse + ")")
+				continue
+			}
+			fmt.Println(class.ClassID)
+		}
+	}
+}
+
+// Generates every valid schedule out of the classes, best rated first
+func generateSchedule(classes []ClassEnhanced, constraints UserScheduleConstraints) []Schedule {
 	type TempClass struct {
 		courseName  string
 		occurrences int
@@ -183,7 +291,7 @@ func generateSchedule(classes []ClassEnhanced) []ClassEnhanced {
 		found = false
 
 		for i, tempClass := range tempClasses {
-			if tempClass.courseName == class.CourseName {
+			if tempClass.courseName == class.requestedCourseCode() {
 				tempClasses[i].occurrences++
 				tempClasses[i].classes = append(tempClasses[i].classes, class)
 				found = true
@@ -192,10 +300,15 @@ func generateSchedule(classes []ClassEnhanced) []ClassEnhanced {
 		}
 
 		if !found {
-			tempClasses = append(tempClasses, TempClass{class.CourseName, 1, []ClassEnhanced{class}})
+			tempClasses = append(tempClasses, TempClass{class.requestedCourseCode(), 1, []ClassEnhanced{class}})
 		}
 	}
 
+	// Nothing to schedule
+	if len(tempClasses) == 0 {
+		return []Schedule{}
+	}
+
 	largestCol := 0
 
 	for _, tempClass := range tempClasses {
@@ -208,26 +321,26 @@ func generateSchedule(classes []ClassEnhanced) []ClassEnhanced {
 		}
 	}
 
-	// Main Algorithm (brute force method, a more efficient method would be better!)
-	type Schedule struct {
-		classes []ClassEnhanced
-		rating  float32
-	}
+	// Each section is scored once, the schedules only add the scores up
+	sectionScores := scoreSections(classes, constraints)
 
+	// Main Algorithm (brute force method, a more efficient method would be better!)
 	possibleSchedules := []Schedule{}
 
 	for _, col1 := range tempClasses[0].classes {
 		if len(tempClasses) == 1 {
 			// we stop here
-			possibleSchedules = append(possibleSchedules, Schedule{[]ClassEnhanced{col1}, col1.InstructorRating})
+			schedule := []ClassEnhanced{col1}
+			possibleSchedules = append(possibleSchedules, Schedule{Classes: schedule, Rating: scoreSchedule(schedule, sectionScores)})
 			continue
 		}
 
 		for _, col2 := range tempClasses[1].classes {
 			if len(tempClasses) == 2 {
 				// we stop here
-				if isScheduleValid([]ClassEnhanced{col1, col2}) {
-					possibleSchedules = append(possibleSchedules, Schedule{[]ClassEnhanced{col1, col2}, col1.InstructorRating + col2.InstructorRating})
+				schedule := []ClassEnhanced{col1, col2}
+				if isScheduleValid(schedule) {
+					possibleSchedules = append(possibleSchedules, Schedule{Classes: schedule, Rating: scoreSchedule(schedule, sectionScores)})
 				}
 				continue
 			}
@@ -235,8 +348,9 @@ func generateSchedule(classes []ClassEnhanced) []ClassEnhanced {
 			for _, col3 := range tempClasses[2].classes {
 				if len(tempClasses) == 3 {
 					// we stop here
-					if isScheduleValid([]ClassEnhanced{col1, col2, col3}) {
-						possibleSchedules = append(possibleSchedules, Schedule{[]ClassEnhanced{col1, col2, col3}, col1.InstructorRating + col2.InstructorRating + col3.InstructorRating})
+					schedule := []ClassEnhanced{col1, col2, col3}
+					if isScheduleValid(schedule) {
+						possibleSchedules = append(possibleSchedules, Schedule{Classes: schedule, Rating: scoreSchedule(schedule, sectionScores)})
 					}
 					continue
 				}
@@ -244,8 +358,9 @@ func generateSchedule(classes []ClassEnhanced) []ClassEnhanced {
 				for _, col4 := range tempClasses[3].classes {
 					if len(tempClasses) == 4 {
 						// we stop here
-						if isScheduleValid([]ClassEnhanced{col1, col2, col3, col4}) {
-							possibleSchedules = append(possibleSchedules, Schedule{[]ClassEnhanced{col1, col2, col3, col4}, col1.InstructorRating + col2.InstructorRating + col3.InstructorRating + col4.InstructorRating})
+						schedule := []ClassEnhanced{col1, col2, col3, col4}
+						if isScheduleValid(schedule) {
+							possibleSchedules = append(possibleSchedules, Schedule{Classes: schedule, Rating: scoreSchedule(schedule, sectionScores)})
 						}
 						continue
 					}
@@ -253,8 +368,9 @@ func generateSchedule(classes []ClassEnhanced) []ClassEnhanced {
 					for _, col5 := range tempClasses[4].classes {
 						if len(tempClasses) == 5 {
 							// we stop here
-							if isScheduleValid([]ClassEnhanced{col1, col2, col3, col4, col5}) {
-								possibleSchedules = append(possibleSchedules, Schedule{[]ClassEnhanced{col1, col2, col3, col4, col5}, col1.InstructorRating + col2.InstructorRating + col3.InstructorRating + col4.InstructorRating + col5.InstructorRating})
+							schedule := []ClassEnhanced{col1, col2, col3, col4, col5}
+							if isScheduleValid(schedule) {
+								possibleSchedules = append(possibleSchedules, Schedule{Classes: schedule, Rating: scoreSchedule(schedule, sectionScores)})
 							}
 							continue
 						}
@@ -262,8 +378,9 @@ func generateSchedule(classes []ClassEnhanced) []ClassEnhanced {
 						for _, col6 := range tempClasses[5].classes {
 							if len(tempClasses) == 6 {
 								// we stop here
-								if isScheduleValid([]ClassEnhanced{col1, col2, col3, col4, col5, col6}) {
-									possibleSchedules = append(possibleSchedules, Schedule{[]ClassEnhanced{col1, col2, col3, col4, col5, col6}, col1.InstructorRating + col2.InstructorRating + col3.InstructorRating + col4.InstructorRating + col5.InstructorRating + col6.InstructorRating})
+								schedule := []ClassEnhanced{col1, col2, col3, col4, col5, col6}
+								if isScheduleValid(schedule) {
+									possibleSchedules = append(possibleSchedules, Schedule{Classes: schedule, Rating: scoreSchedule(schedule, sectionScores)})
 								}
 								continue
 							}
@@ -274,25 +391,12 @@ func generateSchedule(classes []ClassEnhanced) []ClassEnhanced {
 		}
 	}
 
-	fmt.Println("Number of possible schedules: " + strconv.Itoa(len(possibleSchedules)))
-
 	// Sorts all schedules by rating
 	sort.Slice(possibleSchedules, func(i, j int) bool {
-		return possibleSchedules[i].rating > possibleSchedules[j].rating
+		return possibleSchedules[i].Rating > possibleSchedules[j].Rating
 	})
 
-	// Prints first ten best schedules - just printing it out for testing
-	for i := 0; i < 10; i++ {
-		fmt.Println("------------------")
-		fmt.Println("Schedule #" + strconv.Itoa(i+1))
-		fmt.Println("Rating: ")
-		fmt.Println(possibleSchedules[i].rating)
-		for _, class := range possibleSchedules[i].classes {
-			fmt.Println(class.ClassID)
-		}
-	}
-
-	return []ClassEnhanced{}
+	return possibleSchedules
 }
 
 func isScheduleValid(classes []ClassEnhanced) bool {
@@ -384,60 +488,96 @@ func isScheduleValid(classes []ClassEnhanced) bool {
 }
 
 // Filters the courses we want and returns them
+// Course codes are compared normalized, so "MATH 8" finds the sections of "MATH 008"
+// Sections of equivalent courses are included too, with RequestedCourse set to the course they satisfy
 func filterCourses(school School, userScheduleConstraints UserScheduleConstraints) []Class {
 	classes := []Class{}
+	courseCodes := requestedCourseCodes(userScheduleConstraints)
 
 	for _, class := range school.Classes {
-		for _, courseName := range userScheduleConstraints.Courses {
-			if courseName == class.CourseName {
-				classes = append(classes, class)
-			}
+		code := normalizeCourseCode(class.CourseName)
+		requestedCourse, ok := courseCodes[code]
+		if ok && normalizeCourseCode(requestedCourse) != code {
+			class.RequestedCourse = requestedCourse
+		}
+
+		// Locked sections are always wanted, even if their course was not asked for
+		if ok || classIDInList(class.ClassID, userScheduleConstraints.LockedClassIDs) {
+			classes = append(classes, class)
 		}
 	}
 
 	return classes
 }
 
-// TODO: Improve accuracy of this function. It still has trouble matching some of the RMF names to the schedule names
-func integrateRatingsIntoClassData(classes []Class, professors []ProfessorType) []ClassEnhanced {
+// Matches every class instructor to the professor whose name fits best and copies over their rating
+// Instructors with an alias are matched to the aliased professor without comparing names
+// Classes whose instructor matches nobody (or a professor without a rating) get -1
+func integrateRatingsIntoClassData(schoolId string, classes []Class, index *professorIndex, aliases map[string]int) []ClassEnhanced {
 	enhancedClasses := []ClassEnhanced{}
-	found := false
 
 	for _, class := range classes {
-		found = false
-		for _, professor := range professors {
-			if JaroWinklerDistance(class.Instructor, professor.FirstName+" "+professor.MiddleName+" "+professor.LastName) > 0.90 {
-				rating, err := strconv.ParseFloat(professor.OverallRating, 32)
-				if err != nil {
-					break
-				}
-
-				// USEFUL DEBUG INFO
-				//fmt.Print("Instructor: " + class.Instructor)
-				//fmt.Print(" RMF: " + professor.FirstName + " " + professor.MiddleName + " " + professor.LastName)
-				//fmt.Print(" Accuracy: ")
-				//fmt.Print(JaroWinklerDistance(class.Instructor, professor.FirstName+" "+professor.MiddleName+" "+professor.LastName))
-				//fmt.Println()
+		professor, found := aliasedProfessor(class.Instructor, aliases, index)
+		confidence := 1.0
+		if !found {
+			professor, confidence, found = index.match(schoolId, class.CourseName, class.Instructor)
+		}
+		if !found {
+			enhancedClasses = append(enhancedClasses, enhanceClass(class, -1))
+			continue
+		}
 
-				enhancedClasses = append(enhancedClasses, ClassEnhanced{class.CourseName, class.ClassID,
-					class.Instructor, float32(rating), class.Availability,
-					class.InstructionalMethod, class.MeetingTimes, class.Date})
+		// USEFUL DEBUG INFO
+		//fmt.Print("Instructor: " + class.Instructor)
+		//fmt.Print(" RMF: " + professor.FirstName + " " + professor.MiddleName + " " + professor.LastName)
+		//fmt.Print(" Accuracy: ")
+		//fmt.Print(confidence)
+		//fmt.Println()
 
-				found = true
-				break
-			}
+		rating, ok := professorRating(professor)
+		if !ok {
+			rating = -1
 		}
 
-		if !found {
-			enhancedClasses = append(enhancedClasses, ClassEnhanced{class.CourseName, class.ClassID,
-				class.Instructor, -1, class.Availability,
-				class.InstructionalMethod, class.MeetingTimes, class.Date})
+		enhancedClass := enhanceClass(class, rating)
+		enhancedClass.ProfessorID = professor.Id
+		enhancedClass.MatchConfidence = confidence
+		enhancedClass.Difficulty = professor.AvgDifficulty
+		if professor.WouldTakeAgainPercent != nil {
+			enhancedClass.WouldTakeAgain = *professor.WouldTakeAgainPercent
+		}
+		if ok {
+			enhancedClass.RatingCount = professor.TotalRatings
+			enhancedClass.WeightedRating = weightedRating(rating, professor.TotalRatings, index.schoolAverage)
 		}
+		enhancedClasses = append(enhancedClasses, enhancedClass)
 	}
 
 	return enhancedClasses
 }
 
+// Copies a class into a ClassEnhanced with the given instructor rating (-1 if unrated)
+func enhanceClass(class Class, instructorRating float32) ClassEnhanced {
+	return ClassEnhanced{
+		CourseName:          class.CourseName,
+		RequestedCourse:     class.RequestedCourse,
+		ClassID:             class.ClassID,
+		Instructor:          class.Instructor,
+		InstructorRating:    instructorRating,
+		Rated:               instructorRating >= 0,
+		WeightedRating:      instructorRating,
+		WouldTakeAgain:      -1,
+		Availability:        class.AvailabilityStatus(),
+		InstructionalMethod: class.Method,
+		MeetingTimes:        class.MeetingTimes,
+		Date:                class.Date,
+		Capacity:            class.Capacity,
+		Enrolled:            class.Enrolled,
+		WaitlistCapacity:    class.WaitlistCapacity,
+		Waitlisted:          class.Waitlisted,
+	}
+}
+
 func getClassesThatFitScheduleConstraints(classes []Class, constraints UserScheduleConstraints) []Class {
 	newClasses := []Class{}
 
@@ -467,12 +607,28 @@ func getClassesThatFitScheduleConstraints(classes []Class, constraints UserSched
 		return false
 	}
 
+	lockedCourses := lockedCourseNames(classes, constraints)
+
 	for _, class := range classes {
 		fitsSchedule = false
 
+		// Banned sections never make it in, locked sections always do
+		if classIDInList(class.ClassID, constraints.BannedClassIDs) {
+			continue
+		}
+		if classIDInList(class.ClassID, constraints.LockedClassIDs) {
+			newClasses = append(newClasses, class)
+			continue
+		}
+
+		// Other sections of a course with a locked section are not needed
+		if lockedCourses[class.requestedCourseCode()] {
+			continue
+		}
+
 		// Instructional Method
 		for _, preferredInstruction := range constraints.InstructionalMethods {
-			if preferredInstruction == class.InstructionalMethod {
+			if preferredInstruction == class.Method {
 				fitsSchedule = true
 			}
 		}
@@ -481,16 +637,15 @@ func getClassesThatFitScheduleConstraints(classes []Class, constraints UserSched
 		}
 		fitsSchedule = false
 
-		// Availability
-		for _, preferredAvailability := range constraints.Availability {
-			if preferredAvailability == class.Availability {
-				fitsSchedule = true
-			}
+		// Availability, waitlist and open seats
+		if !fitsAvailabilityConstraints(class, constraints) {
+			continue
 		}
-		if !fitsSchedule {
+
+		// Instructors
+		if !fitsInstructorConstraints(class, constraints) {
 			continue
 		}
-		fitsSchedule = false
 
 		// if class has no meeting times
 		if len(class.MeetingTimes) == 0 {
@@ -579,8 +734,13 @@ func getClassesThatFitScheduleConstraints(classes []Class, constraints UserSched
 }
 
 // TODO: This will be fine for the first prototype, but we need to cache data and not fetch it everytime
-// Fetches most recent class data from MongoDB
+// Fetches most recent class data of a school from MongoDB
 func fetchClassData(schoolId string) (School, error) {
+	schoolConfig, err := lookupSchool(schoolId)
+	if err != nil {
+		return School{}, err
+	}
+
 	// Get collection from database
 	collection, err := db.GetDBCollection("Classes")
 
@@ -591,7 +751,7 @@ func fetchClassData(schoolId string) (School, error) {
 
 	// Search for specified course in database
 	opts := options.FindOne().SetSort(bson.M{"$natural": -1}) // starts searching from most recent documents
-	cursor := collection.FindOne(context.TODO(), bson.M{"schoolid": schoolId}, opts)
+	cursor := collection.FindOne(context.TODO(), bson.M{"schoolid": schoolConfig.ClassDataKey}, opts)
 
 	// Deserialize result
 	var elem School
@@ -604,7 +764,13 @@ func fetchClassData(schoolId string) (School, error) {
 	return elem, nil
 }
 
+// Fetches most recent rate my professor data of a school from MongoDB
 func fetchProfessorData(schoolId string) (ProfessorExport, error) {
+	schoolConfig, err := lookupSchool(schoolId)
+	if err != nil {
+		return ProfessorExport{}, err
+	}
+
 	// Get collection from database
 	collection, err := db.GetDBCollection("Professors")
 
@@ -615,7 +781,7 @@ func fetchProfessorData(schoolId string) (ProfessorExport, error) {
 
 	// Search for specified course in database
 	opts := options.FindOne().SetSort(bson.M{"$natural": -1}) // starts searching from most recent documents
-	cursor := collection.FindOne(context.TODO(), bson.M{"schoolid": schoolId}, opts)
+	cursor := collection.FindOne(context.TODO(), bson.M{"schoolid": schoolConfig.RatingSourceID}, opts)
 
 	// Deserialize result
 	var elem ProfessorExport
@@ -631,11 +797,15 @@ func fetchProfessorData(schoolId string) (ProfessorExport, error) {
 // JaroWinklerDistance Used this to get better matches between instructor names in schedule database and instructor names in rate my professor database
 // Some of the names in the rate my professor database are not spelled the same as schedule database
 // This function is an attempt to match names based on similarity.
+// Both names are normalized first (see normalizeName) and compared rune by rune, so accented names work
 // Ref: https://socketloop.com/tutorials/golang-how-to-find-out-similarity-between-two-strings-with-jaro-winkler-distance
 func JaroWinklerDistance(s1, s2 string) float64 {
 
-	s1Matches := make([]bool, len(s1)) // |s1|
-	s2Matches := make([]bool, len(s2)) // |s2|
+	r1 := []rune(normalizeName(s1))
+	r2 := []rune(normalizeName(s2))
+
+	r1Matches := make([]bool, len(r1)) // |s1|
+	r2Matches := make([]bool, len(r2)) // |s2|
 
 	var matchingCharacters = 0.0
 	var transpositions = 0.0
@@ -643,47 +813,42 @@ func JaroWinklerDistance(s1, s2 string) float64 {
 	// sanity checks
 
 	// return 0 if either one is empty string
-	if len(s1) == 0 || len(s2) == 0 {
+	if len(r1) == 0 || len(r2) == 0 {
 		return 0 // no similarity
 	}
 
-	// return 1 if both strings are empty
-	if len(s1) == 0 && len(s2) == 0 {
-		return 1 // exact match
-	}
-
-	if strings.EqualFold(s1, s2) { // case insensitive
+	if string(r1) == string(r2) { // already case folded
 		return 1 // exact match
 	}
 
 	// Two characters from s1 and s2 respectively,
 	// are considered matching only if they are the same and not farther than
 	// [ max(|s1|,|s2|) / 2 ] - 1
-	matchDistance := len(s1)
-	if len(s2) > matchDistance {
-		matchDistance = len(s2)
+	matchDistance := len(r1)
+	if len(r2) > matchDistance {
+		matchDistance = len(r2)
 	}
 	matchDistance = matchDistance/2 - 1
 
 	// Each character of s1 is compared with all its matching characters in s2
-	for i := range s1 {
+	for i := range r1 {
 		low := i - matchDistance
 		if low < 0 {
 			low = 0
 		}
 		high := i + matchDistance + 1
-		if high > len(s2) {
-			high = len(s2)
+		if high > len(r2) {
+			high = len(r2)
 		}
 		for j := low; j < high; j++ {
-			if s2Matches[j] {
+			if r2Matches[j] {
 				continue
 			}
-			if s1[i] != s2[j] {
+			if r1[i] != r2[j] {
 				continue
 			}
-			s1Matches[i] = true
-			s2Matches[j] = true
+			r1Matches[i] = true
+			r2Matches[j] = true
 			matchingCharacters++
 			break
 		}
@@ -696,21 +861,21 @@ func JaroWinklerDistance(s1, s2 string) float64 {
 	// Count the transpositions.
 	// The number of matching (but different sequence order) characters divided by 2 defines the number of transpositions
 	k := 0
-	for i := range s1 {
-		if !s1Matches[i] {
+	for i := range r1 {
+		if !r1Matches[i] {
 			continue
 		}
-		for !s2Matches[k] {
+		for !r2Matches[k] {
 			k++
 		}
-		if s1[i] != s2[k] {
+		if r1[i] != r2[k] {
 			transpositions++ // increase transpositions
 		}
 		k++
 	}
 	transpositions /= 2
 
-	weight := (matchingCharacters/float64(len(s1)) + matchingCharacters/float64(len(s2)) + (matchingCharacters-transpositions)/matchingCharacters) / 3
+	weight := (matchingCharacters/float64(len(r1)) + matchingCharacters/float64(len(r2)) + (matchingCharacters-transpositions)/matchingCharacters) / 3
 
 	//  the length of common prefix at the start of the string up to a maximum of four characters
 	l := 0
@@ -719,12 +884,8 @@ func JaroWinklerDistance(s1, s2 string) float64 {
 	//The standard value for this constant in Winkler's work is {\displaystyle p=0.1}p=0.1
 	p := 0.1
 
-	// make it easier for s1[l] == s2[l] comparison
-	s1 = strings.ToLower(s1)
-	s2 = strings.ToLower(s2)
-
 	if weight > 0.7 {
-		for (l < 4) && s1[l] == s2[l] {
+		for l < 4 && l < len(r1) && l < len(r2) && r1[l] == r2[l] {
 			l++
 		}
 
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"strings"
+)
+
+// Name parts of a person, normalized with normalizeName
+type personName struct {
+	first  string
+	middle string
+	last   string
+}
+
+// Generational suffixes that are not part of the last name
+var nameSuffixes = map[string]bool{"jr": true, "sr": true, "ii": true, "iii": true, "iv": true}
+
+// Parses an instructor name as it appears in schedule data
+// Understands "First Middle Last", "F. Last" and "Last, First Middle"
+func parseInstructorName(name string) personName {
+	if comma := strings.Index(name, ","); comma != -1 {
+		last := normalizeName(name[:comma])
+		words := strings.Fields(normalizeName(name[comma+1:]))
+		words = removeNameSuffixes(words)
+
+		parsed := personName{last: last}
+		if len(words) > 0 {
+			parsed.first = words[0]
+			parsed.middle = strings.Join(words[1:], " ")
+		}
+		return parsed
+	}
+
+	words := removeNameSuffixes(strings.Fields(normalizeName(name)))
+
+	switch len(words) {
+	case 0:
+		return personName{}
+	case 1:
+		return personName{last: words[0]}
+	default:
+		return personName{first: words[0], middle: strings.Join(words[1:len(words)-1], " "), last: words[len(words)-1]}
+	}
+}
+
+// Builds the name parts of a rate my professor professor
+func professorName(professor ProfessorType) personName {
+	return personName{
+		first:  normalizeName(professor.FirstName),
+		middle: normalizeName(professor.MiddleName),
+		last:   normalizeName(professor.LastName),
+	}
+}
+
+func removeNameSuffixes(words []string) []string {
+	for len(words) > 1 && nameSuffixes[words[len(words)-1]] {
+		words = words[:len(words)-1]
+	}
+
+	return words
+}
+
+// Compares two first (or middle) names, allowing either one to be an initial
+func compareGivenNames(name1 string, name2 string) float64 {
+	if name1 == "" || name2 == "" {
+		// Nothing to compare, neither confirms nor rules out a match
+		return 0.8
+	}
+
+	if len([]rune(name1)) == 1 || len([]rune(name2)) == 1 {
+		if []rune(name1)[0] == []rune(name2)[0] {
+			return 0.9
+		}
+		return 0
+	}
+
+	return JaroWinklerDistance(name1, name2)
+}
+
+// Compares two last names, letting a single word last name match one word of a compound one ("pena" and "de la pena")
+func compareLastNames(name1 string, name2 string) float64 {
+	best := JaroWinklerDistance(name1, name2)
+
+	words1 := strings.Fields(name1)
+	words2 := strings.Fields(name2)
+	if len(words1) != 1 && len(words2) != 1 {
+		return best
+	}
+
+	for _, word1 := range words1 {
+		for _, word2 := range words2 {
+			if similarity := JaroWinklerDistance(word1, word2); similarity > best {
+				best = similarity
+			}
+		}
+	}
+
+	return best
+}
+
+// Scores how likely two parsed names belong to the same person, between 0 and 1
+func comparePersonNames(name1 personName, name2 personName) float64 {
+	if name1.last == "" || name2.last == "" {
+		return 0
+	}
+
+	// The last name carries most of the weight, the first name confirms it
+	confidence := 0.6*compareLastNames(name1.last, name2.last) + 0.4*compareGivenNames(name1.first, name2.first)
+
+	// Middle names only count against a match when both are known and their initials differ
+	if name1.middle != "" && name2.middle != "" && compareGivenNames(name1.middle, name2.middle) == 0 {
+		confidence -= 0.05
+	}
+
+	return confidence
+}
+
+// Scores how likely two names belong to the same person, also trying the first name swapped with the last name
+// when the first name has no comma ("Smith John")
+func compareNames(instructor string, name personName) float64 {
+	parsed := parseInstructorName(instructor)
+	confidence := comparePersonNames(parsed, name)
+
+	if !strings.Contains(instructor, ",") && parsed.first != "" {
+		swapped := personName{first: parsed.last, middle: parsed.middle, last: parsed.first}
+		if swappedConfidence := comparePersonNames(swapped, name); swappedConfidence > confidence {
+			confidence = swappedConfidence
+		}
+	}
+
+	return confidence
+}
+
+// Ranking bonus for professors in the department of the course being matched
+// Lets a same-department professor win over a slightly better named professor from another department
+const sameDepartmentBonus = 0.05
+
+// Finds the professor that best matches the instructor of a course
+// Professors in the course's department are preferred, and win ties
+// Professors with a rating always win over matching professors without one (e.g. a duplicate profile)
+// Returns false if no professor matches with a confidence above instructorMatchThreshold, or if several professors
+// tie for the best match (e.g. "Smith" or "J. Smith" with both a John and a Jane Smith), since picking one would be a guess
+func matchInstructor(schoolId string, courseName string, instructor string, professors []ProfessorType) (ProfessorType, float64, bool) {
+	best := ProfessorType{}
+	bestConfidence := 0.0
+	bestRank := 0.0
+	bestRated := false
+	found := false
+	ambiguous := false
+
+	for _, professor := range professors {
+		confidence := compareNames(instructor, professorName(professor))
+		if confidence <= instructorMatchThreshold {
+			continue
+		}
+
+		rank := confidence
+		if inCourseDepartment(schoolId, courseName, professor.Department) {
+			rank += sameDepartmentBonus
+		}
+
+		_, rated := professorRating(professor)
+		if bestRated && !rated {
+			continue
+		}
+
+		if rank > bestRank || (rated && !bestRated) {
+			best = professor
+			bestConfidence = confidence
+			bestRank = rank
+			bestRated = rated
+			found = true
+			ambiguous = false
+		} else if rank == bestRank {
+			ambiguous = true
+		}
+	}
+
+	if ambiguous {
+		return ProfessorType{}, 0, false
+	}
+
+	return best, bestConfidence, found
+}
+
+// Finds the professor whose name is closest to the instructor name, however poor the match
+func closestProfessor(instructor string, professors []ProfessorType) (ProfessorType, float64) {
+	closest := ProfessorType{}
+	closestConfidence := 0.0
+
+	for _, professor := range professors {
+		confidence := compareNames(instructor, professorName(professor))
+		if confidence > closestConfidence {
+			closest = professor
+			closestConfidence = confidence
+		}
+	}
+
+	return closest, closestConfidence
+}
+
+// Returns the full name of a professor
+func professorFullName(professor ProfessorType) string {
+	return strings.Join(strings.Fields(professor.FirstName+" "+professor.MiddleName+" "+professor.LastName), " ")
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestParseInstructorName(t *testing.T) {
+	tests := []struct {
+		name string
+		want personName
+	}{
+		{"John Smith", personName{first: "john", last: "smith"}},
+		{"John Quincy Adams", personName{first: "john", middle: "quincy", last: "adams"}},
+		{"J. Smith", personName{first: "j", last: "smith"}},
+		{"Smith, John", personName{first: "john", last: "smith"}},
+		{"Smith, John Q.", personName{first: "john", middle: "q", last: "smith"}},
+		{"de la Peña, José", personName{first: "jose", last: "de la pena"}},
+		{"Martin Luther King Jr.", personName{first: "martin", middle: "luther", last: "king"}},
+		{"Smith, John III", personName{first: "john", last: "smith"}},
+		{"Smith", personName{last: "smith"}},
+		{"Jr", personName{last: "jr"}},
+		{"", personName{}},
+		{"  ", personName{}},
+	}
+
+	for _, test := range tests {
+		if got := parseInstructorName(test.name); got != test.want {
+			t.Errorf("parseInstructorName(%q) = %+v, want %+v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestMatchInstructor(t *testing.T) {
+	professor := func(id int, first string, last string, rating NumericRating) ProfessorType {
+		totalRatings := 0
+		if rating >= 0 {
+			totalRatings = 10
+		}
+		return ProfessorType{Id: id, FirstName: first, LastName: last, OverallRating: rating, TotalRatings: totalRatings}
+	}
+
+	johnSmith := professor(1, "John", "Smith", 4)
+	janeSmith := professor(2, "Jane", "Smith", 3)
+	maryJones := professor(3, "Mary", "Jones", 5)
+	johnSmithUnrated := professor(4, "John", "Smith", -1)
+	mariaDeLaPena := professor(5, "Maria", "de la Peña", 4)
+
+	tests := []struct {
+		name       string
+		instructor string
+		professors []ProfessorType
+		wantID     int
+		wantFound  bool
+	}{
+		{"full name", "John Smith", []ProfessorType{janeSmith, johnSmith, maryJones}, 1, true},
+		{"last name first", "Smith, Jane", []ProfessorType{johnSmith, janeSmith}, 2, true},
+		{"last name only with one candidate", "Jones", []ProfessorType{johnSmith, maryJones}, 3, true},
+		{"word of a compound last name", "Maria Pena", []ProfessorType{johnSmith, mariaDeLaPena}, 5, true},
+		{"last name only is ambiguous", "Smith", []ProfessorType{johnSmith, janeSmith}, 0, false},
+		{"last name only is ambiguous in either order", "Smith", []ProfessorType{janeSmith, johnSmith}, 0, false},
+		{"shared initial is ambiguous", "J. Smith", []ProfessorType{johnSmith, janeSmith, maryJones}, 0, false},
+		{"rated wins over an unrated duplicate", "John Smith", []ProfessorType{johnSmithUnrated, johnSmith}, 1, true},
+		{"different initial does not match", "M. Smith", []ProfessorType{johnSmith, janeSmith}, 0, false},
+		{"no professors", "John Smith", nil, 0, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, _, found := matchInstructor("", "", test.instructor, test.professors)
+			if found != test.wantFound || got.Id != test.wantID {
+				t.Errorf("matchInstructor(%q) = %d, %v, want %d, %v", test.instructor, got.Id, found, test.wantID, test.wantFound)
+			}
+		})
+	}
+}
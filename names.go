@@ -0,0 +1,48 @@
+package main
+
+import (
+	"strings"
+	"sync"
+	"unicode"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Marks that are dropped without leaving a space, so "O'Brien" and "OBrien" compare the same
+var nameApostrophes = "'’`´"
+
+// Transformers removing diacritics, pooled since a chain keeps state and cannot be shared between goroutines
+var stripDiacriticsPool = sync.Pool{
+	New: func() interface{} {
+		return transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	},
+}
+
+// Normalizes a name before it is compared to another one
+// Strips diacritics ("José Peña" -> "jose pena"), folds case, and turns punctuation and runs of whitespace into single spaces
+func normalizeName(name string) string {
+	stripDiacritics := stripDiacriticsPool.Get().(transform.Transformer)
+	stripped, _, err := transform.String(stripDiacritics, name)
+	stripDiacriticsPool.Put(stripDiacritics)
+	if err != nil {
+		stripped = name
+	}
+
+	folded := cases.Fold().String(stripped)
+
+	folded = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(nameApostrophes, r) {
+			return -1
+		}
+		return r
+	}, folded)
+
+	words := strings.FieldsFunc(folded, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestJaroWinklerDistance(t *testing.T) {
+	tests := []struct {
+		name string
+		s1   string
+		s2   string
+		want float64
+	}{
+		{"both empty", "", "", 0},
+		{"one empty", "smith", "", 0},
+		{"punctuation only", "'-.", "smith", 0},
+		{"single equal characters", "a", "a", 1},
+		{"single different characters", "a", "b", 0},
+		{"two characters swapped", "ab", "ba", 0},
+		{"identical", "Smith", "Smith", 1},
+		{"case folded", "SMITH", "smith", 1},
+		{"accents stripped", "José Peña", "Jose Pena", 1},
+		{"accented against typo", "Müller", "Muler", 0.9611},
+		{"transposition", "MARTHA", "MARHTA", 0.9611},
+		{"unequal lengths", "DWAYNE", "DUANE", 0.84},
+		{"unequal lengths reversed", "DUANE", "DWAYNE", 0.84},
+		{"long against short", "DIXON", "DICKSONX", 0.8133},
+		{"nothing in common", "abc", "xyz", 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := JaroWinklerDistance(test.s1, test.s2)
+			if math.Abs(got-test.want) > 0.0001 {
+				t.Errorf("JaroWinklerDistance(%q, %q) = %.4f, want %.4f", test.s1, test.s2, got, test.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"José Peña", "jose pena"},
+		{"  O'Brien,   Pat ", "obrien pat"},
+		{"Smith-Jones", "smith jones"},
+		{"ÅSTRÖM", "astrom"},
+		{"", ""},
+	}
+
+	for _, test := range tests {
+		if got := normalizeName(test.name); got != test.want {
+			t.Errorf("normalizeName(%q) = %q, want %q", test.name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Returns true if the class id is in the list
+func classIDInList(classID string, classIDs []string) bool {
+	for _, id := range classIDs {
+		if id == classID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Returns the names of the (requested) courses that have a locked section
+func lockedCourseNames(classes []Class, constraints UserScheduleConstraints) map[string]bool {
+	courseNames := map[string]bool{}
+
+	for _, class := range classes {
+		if classIDInList(class.ClassID, constraints.LockedClassIDs) {
+			courseNames[class.requestedCourseCode()] = true
+		}
+	}
+
+	return courseNames
+}
+
+// Checks that the locked sections exist and are consistent with the rest of the constraints
+// Returns one error per problem found
+func validateLockedClasses(school School, constraints UserScheduleConstraints) []error {
+	problems := []error{}
+	lockedClasses := []ClassEnhanced{}
+	lockedCourses := map[string]string{}
+
+	// Constraints without the locks (or bans, reported on their own) so we can see if a locked section
+	// would have passed the filters on its own
+	unlocked := constraints
+	unlocked.LockedClassIDs = nil
+	unlocked.BannedClassIDs = nil
+
+	// Locked sections with the course they were picked for, so equivalent courses count as the same course
+	classes := filterCourses(school, constraints)
+
+	for _, classID := range constraints.LockedClassIDs {
+		if classIDInList(classID, constraints.BannedClassIDs) {
+			problems = append(problems, fmt.Errorf("class %s is both locked and banned", classID))
+		}
+
+		found := false
+		for _, class := range classes {
+			if class.ClassID != classID {
+				continue
+			}
+			found = true
+
+			courseName := class.CourseName
+			if class.RequestedCourse != "" {
+				courseName = class.RequestedCourse
+			}
+			if otherID, ok := lockedCourses[class.requestedCourseCode()]; ok {
+				problems = append(problems, fmt.Errorf("classes %s and %s are both locked for course %s", otherID, classID, courseName))
+			}
+			lockedCourses[class.requestedCourseCode()] = classID
+
+			if len(getClassesThatFitScheduleConstraints([]Class{class}, unlocked)) == 0 {
+				problems = append(problems, fmt.Errorf("class %s does not fit the schedule constraints", classID))
+			}
+
+			lockedClasses = append(lockedClasses, enhanceClass(class, -1))
+			break
+		}
+
+		if !found {
+			problems = append(problems, fmt.Errorf("class %s was not found", classID))
+		}
+	}
+
+	if !isScheduleValid(lockedClasses) {
+		problems = append(problems, fmt.Errorf("locked classes have conflicting meeting times"))
+	}
+
+	return problems
+}
+
+// Reports the locked sections that cannot be satisfied and the courses that have no sections,
+// with the course codes the user may have meant
+func reportConstraintProblems(output io.Writer, school School, constraints UserScheduleConstraints) {
+	for _, problem := range validateLockedClasses(school, constraints) {
+		fmt.Fprintln(output, "Locked class: "+problem.Error())
+	}
+
+	for _, courseName := range missingCourses(filterCourses(school, constraints), constraints) {
+		fmt.Fprint(output, "No sections found for "+courseName)
+		if suggestions := suggestCourseCodes(school, courseName, 3); len(suggestions) > 0 {
+			fmt.Fprint(output, ", did you mean "+strings.Join(suggestions, ", ")+"?")
+		}
+		fmt.Fprintln(output)
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func pinnedTestSchool() School {
+	return School{SchoolId: "pcc", Classes: []Class{
+		{ClassID: "100", CourseName: "MATH 5A", Method: MethodInPerson, Availability: "open", MeetingTimes: []MeetingTime{testMeeting("MW", Time{8, 0}, Time{9, 0})}},
+		{ClassID: "101", CourseName: "MATH 5A", Method: MethodInPerson, Availability: "open", MeetingTimes: []MeetingTime{testMeeting("MW", Time{10, 0}, Time{11, 0})}},
+		{ClassID: "102", CourseName: "MATH 5A", Method: MethodInPerson, Availability: "open", MeetingTimes: []MeetingTime{testMeeting("MW", Time{18, 0}, Time{19, 0})}},
+		{ClassID: "200", CourseName: "ENGL 1A", Method: MethodInPerson, Availability: "open", MeetingTimes: []MeetingTime{testMeeting("M", Time{8, 30}, Time{9, 30})}},
+		{ClassID: "201", CourseName: "ENGL 1AH", Method: MethodInPerson, Availability: "open", MeetingTimes: []MeetingTime{testMeeting("W", Time{10, 0}, Time{11, 0})}},
+	}}
+}
+
+func pinnedTestConstraints() UserScheduleConstraints {
+	morning := []TimeRange{{Time{8, 0}, Time{12, 0}}}
+
+	return UserScheduleConstraints{
+		SchoolId:             "pcc",
+		Courses:              []string{"MATH 5A", "ENGL 1A"},
+		InstructionalMethods: []InstructionalMethod{MethodInPerson},
+		Availability:         []AvailabilityStatus{AvailabilityOpen},
+		MondayTime:           morning,
+		WednesdayTime:        morning,
+	}
+}
+
+func TestLockedAndBannedClasses(t *testing.T) {
+	tests := []struct {
+		name   string
+		locked []string
+		banned []string
+		want   []string
+	}{
+		{"no locks or bans", nil, nil, []string{"100", "101", "200"}},
+		{"banned section", nil, []string{"100"}, []string{"101", "200"}},
+		{"locked section replaces the rest of its course", []string{"101"}, nil, []string{"101", "200"}},
+		{"locked section outside the constraints", []string{"102"}, nil, []string{"102", "200"}},
+		{"locked and banned", []string{"101"}, []string{"101"}, []string{"200"}},
+	}
+
+	for _, test := range tests {
+		constraints := pinnedTestConstraints()
+		constraints.LockedClassIDs = test.locked
+		constraints.BannedClassIDs = test.banned
+
+		got := []string{}
+		for _, class := range getClassesThatFitScheduleConstraints(filterCourses(pinnedTestSchool(), constraints), constraints) {
+			got = append(got, class.ClassID)
+		}
+		sort.Strings(got)
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: sections = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestValidateLockedClasses(t *testing.T) {
+	tests := []struct {
+		name        string
+		locked      []string
+		banned      []string
+		equivalents [][]string
+		want        []string
+	}{
+		{"valid", []string{"101", "200"}, nil, nil, []string{}},
+		{"not found", []string{"999"}, nil, nil, []string{"class 999 was not found"}},
+		{"locked and banned", []string{"101"}, []string{"101"}, nil, []string{"class 101 is both locked and banned"}},
+		{"two sections of a course", []string{"100", "101"}, nil, nil, []string{"classes 100 and 101 are both locked for course MATH 5A"}},
+		{"two sections of equivalent courses", []string{"200", "201"}, nil, [][]string{{"ENGL 1A", "ENGL 1AH"}},
+			[]string{"classes 200 and 201 are both locked for course ENGL 1A"}},
+		{"outside the constraints", []string{"102"}, nil, nil, []string{"class 102 does not fit the schedule constraints"}},
+		{"meeting times conflict", []string{"100", "200"}, nil, nil, []string{"locked classes have conflicting meeting times"}},
+	}
+
+	for _, test := range tests {
+		constraints := pinnedTestConstraints()
+		constraints.LockedClassIDs = test.locked
+		constraints.BannedClassIDs = test.banned
+		constraints.CourseEquivalents = test.equivalents
+
+		got := []string{}
+		for _, problem := range validateLockedClasses(pinnedTestSchool(), constraints) {
+			got = append(got, problem.Error())
+		}
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: problems = %q, want %q", test.name, got, test.want)
+		}
+	}
+}
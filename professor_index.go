@@ -0,0 +1,203 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Result of matching an instructor to a professor
+type instructorMatch struct {
+	professor  ProfessorType
+	confidence float64
+	found      bool
+}
+
+// Index of the professors of a ProfessorExport, so each instructor is only compared to the few professors
+// with the same (or a similar sounding) last name, or the same first name, instead of every professor
+type professorIndex struct {
+	professors  []ProfessorType
+	byID        map[int]int      // professor id -> position in professors
+	byLastName  map[string][]int // every word of the normalized last name -> positions in professors
+	byPhonetic  map[string][]int // soundex of every word of the last name -> positions in professors
+	byFirstName map[string][]int // every word of the normalized first name -> positions in professors
+
+	schoolAverage float32 // average overall rating of the rated professors
+
+	mutex   sync.Mutex
+	matches map[string]instructorMatch // cached matches, keyed by instructor and course subject
+}
+
+// Indexes already built, keyed by school id and export timestamp
+var professorIndexes = map[string]*professorIndex{}
+var professorIndexesMutex sync.Mutex
+
+// Returns the index of a ProfessorExport, building it the first time the export is seen
+func professorIndexFor(export ProfessorExport) *professorIndex {
+	key := export.SchoolId + "/" + strconv.FormatInt(export.Timestamp, 10)
+
+	professorIndexesMutex.Lock()
+	defer professorIndexesMutex.Unlock()
+
+	if index, ok := professorIndexes[key]; ok {
+		return index
+	}
+
+	// Older exports of the same school will not be used again
+	for oldKey := range professorIndexes {
+		if strings.HasPrefix(oldKey, export.SchoolId+"/") {
+			delete(professorIndexes, oldKey)
+		}
+	}
+
+	index := newProfessorIndex(export.Professors)
+	professorIndexes[key] = index
+
+	return index
+}
+
+func newProfessorIndex(professors []ProfessorType) *professorIndex {
+	index := &professorIndex{
+		professors:  professors,
+		byID:        map[int]int{},
+		byLastName:  map[string][]int{},
+		byPhonetic:  map[string][]int{},
+		byFirstName: map[string][]int{},
+		matches:     map[string]instructorMatch{},
+
+		schoolAverage: schoolAverageRating(professors),
+	}
+
+	for i, professor := range professors {
+		index.byID[professor.Id] = i
+
+		for _, word := range strings.Fields(normalizeName(professor.LastName)) {
+			index.byLastName[word] = append(index.byLastName[word], i)
+			if code := soundex(word); code != "" {
+				index.byPhonetic[code] = append(index.byPhonetic[code], i)
+			}
+		}
+
+		for _, word := range strings.Fields(normalizeName(professor.FirstName)) {
+			index.byFirstName[word] = append(index.byFirstName[word], i)
+		}
+	}
+
+	return index
+}
+
+// Returns the professor with the given rate my professor id
+func (index *professorIndex) professorByID(id int) (ProfessorType, bool) {
+	i, ok := index.byID[id]
+	if !ok {
+		return ProfessorType{}, false
+	}
+
+	return index.professors[i], true
+}
+
+// Returns the professors that could be the instructor: the ones sharing a word of the last name, or its sound,
+// and the ones sharing a word of the first name, which catches misspellings of the first letter of the last name
+// ("Smith" and "Cmith" share neither a word nor a soundex code)
+// Both names are looked up in every index, in case the instructor is written "Last First"
+func (index *professorIndex) candidates(instructor string) []ProfessorType {
+	parsed := parseInstructorName(instructor)
+	words := strings.Fields(parsed.last + " " + parsed.first)
+
+	seen := map[int]bool{}
+	candidates := []ProfessorType{}
+
+	add := func(positions []int) {
+		for _, i := range positions {
+			if !seen[i] {
+				seen[i] = true
+				candidates = append(candidates, index.professors[i])
+			}
+		}
+	}
+
+	for _, word := range words {
+		add(index.byLastName[word])
+		if code := soundex(word); code != "" {
+			add(index.byPhonetic[code])
+		}
+		add(index.byFirstName[word])
+	}
+
+	return candidates
+}
+
+// Finds the professor that best matches the instructor of a course, see matchInstructor
+// Results are cached per instructor and course subject, instructors that were not found included
+func (index *professorIndex) match(schoolId string, courseName string, instructor string) (ProfessorType, float64, bool) {
+	key := instructor + "|" + courseSubject(courseName)
+
+	index.mutex.Lock()
+	cached, ok := index.matches[key]
+	index.mutex.Unlock()
+
+	if ok {
+		return cached.professor, cached.confidence, cached.found
+	}
+
+	professor, confidence, found := matchInstructor(schoolId, courseName, instructor, index.candidates(instructor))
+
+	index.mutex.Lock()
+	index.matches[key] = instructorMatch{professor, confidence, found}
+	index.mutex.Unlock()
+
+	return professor, confidence, found
+}
+
+// Letters that sound alike share a soundex digit, vowels (and h, w, y) have none
+var soundexDigits = map[rune]byte{
+	'b': '1', 'f': '1', 'p': '1', 'v': '1',
+	'c': '2', 'g': '2', 'j': '2', 'k': '2', 'q': '2', 's': '2', 'x': '2', 'z': '2',
+	'd': '3', 't': '3',
+	'l': '4',
+	'm': '5', 'n': '5',
+	'r': '6',
+}
+
+// American soundex code of a normalized word, e.g. "robert" -> "r163"
+// Returns "" for words that do not start with a latin letter
+func soundex(word string) string {
+	code := []byte{}
+	var last byte
+
+	for _, r := range word {
+		if r < 'a' || r > 'z' {
+			if len(code) == 0 {
+				return ""
+			}
+			continue
+		}
+
+		digit := soundexDigits[r]
+
+		if len(code) == 0 {
+			code = append(code, byte(r))
+			last = digit
+			continue
+		}
+
+		// h and w do not separate letters with the same digit, vowels do
+		if r == 'h' || r == 'w' {
+			continue
+		}
+
+		if digit != 0 && digit != last {
+			code = append(code, digit)
+			if len(code) == 4 {
+				break
+			}
+		}
+		last = digit
+	}
+
+	for len(code) > 0 && len(code) < 4 {
+		code = append(code, '0')
+	}
+
+	return string(code)
+}
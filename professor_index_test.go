@@ -0,0 +1,64 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestSoundex(t *testing.T) {
+	tests := []struct {
+		word string
+		want string
+	}{
+		{"robert", "r163"},
+		{"rupert", "r163"},
+		{"rubin", "r150"},
+		{"ashcraft", "a261"},
+		{"ashcroft", "a261"},
+		{"tymczak", "t522"},
+		{"pfister", "p236"},
+		{"honeyman", "h555"},
+		{"lee", "l000"},
+		{"a", "a000"},
+		{"o'brien", "o165"},
+		{"", ""},
+		{"4th", ""},
+		{"łukasz", ""},
+	}
+
+	for _, test := range tests {
+		if got := soundex(test.word); got != test.want {
+			t.Errorf("soundex(%q) = %q, want %q", test.word, got, test.want)
+		}
+	}
+}
+
+func TestProfessorIndexMatch(t *testing.T) {
+	professors := []ProfessorType{
+		{Id: 1, FirstName: "John", LastName: "Smith", OverallRating: 4, TotalRatings: 10},
+		{Id: 2, FirstName: "Maria", LastName: "de la Peña", OverallRating: 5, TotalRatings: 3},
+		{Id: 3, FirstName: "Robert", LastName: "Rupert", OverallRating: 3, TotalRatings: 8},
+	}
+
+	tests := []struct {
+		name       string
+		instructor string
+		wantID     int
+		wantFound  bool
+	}{
+		{"same last name", "John Smith", 1, true},
+		{"word of a compound last name", "Maria Pena", 2, true},
+		{"similar sounding last name", "Robert Rubert", 3, true},
+		{"first letter misspelled", "John Cmith", 1, true},
+		{"no such professor", "Alice Walker", 0, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			index := newProfessorIndex(professors)
+			got, _, found := index.match("", "", test.instructor)
+			if found != test.wantFound || got.Id != test.wantID {
+				t.Errorf("match(%q) = %d, %v, want %d, %v", test.instructor, got.Id, found, test.wantID, test.wantFound)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"schedulegenerator/db"
+	"time"
+)
+
+// ConstraintProfile is a named set of schedule constraints a user saved to generate schedules with again
+type ConstraintProfile struct {
+	UserId      string                  `json:"userId"`
+	Name        string                  `json:"name"` // unique per user
+	UpdatedAt   int64                   `json:"updatedAt"`
+	Constraints UserScheduleConstraints `json:"constraints"`
+}
+
+// Fetches the constraint profiles of a user, sorted by name
+func fetchConstraintProfiles(userId string) ([]ConstraintProfile, error) {
+	// Get collection from database
+	collection, err := db.GetDBCollection("ConstraintProfiles")
+
+	if err != nil {
+		fmt.Println(err)
+		return nil, errors.New("unable to fetch collection from database")
+	}
+
+	opts := options.Find().SetSort(bson.M{"name": 1})
+	cursor, err := collection.Find(context.TODO(), bson.M{"userid": userId}, opts)
+	if err != nil {
+		return nil, errors.New("unable to fetch constraint profiles")
+	}
+
+	// Deserialize result
+	var elems []ConstraintProfile
+	err = cursor.All(context.TODO(), &elems)
+
+	if err != nil {
+		return nil, errors.New("unable to decode constraint profiles")
+	}
+
+	return elems, nil
+}
+
+// Fetches one constraint profile of a user by name
+func fetchConstraintProfile(userId string, name string) (ConstraintProfile, error) {
+	// Get collection from database
+	collection, err := db.GetDBCollection("ConstraintProfiles")
+
+	if err != nil {
+		fmt.Println(err)
+		return ConstraintProfile{}, errors.New("unable to fetch collection from database")
+	}
+
+	cursor := collection.FindOne(context.TODO(), bson.M{"userid": userId, "name": name})
+
+	// Deserialize result
+	var elem ConstraintProfile
+	err = cursor.Decode(&elem)
+
+	if err != nil {
+		return ConstraintProfile{}, errors.New("did not find specified profile")
+	}
+
+	return elem, nil
+}
+
+// Saves a constraint profile, replacing the user's existing profile with the same name
+func saveConstraintProfile(profile ConstraintProfile) error {
+	if profile.UserId == "" || profile.Name == "" {
+		return errors.New("a constraint profile needs a user id and a name")
+	}
+
+	if _, err := lookupSchool(profile.Constraints.SchoolId); err != nil {
+		return err
+	}
+
+	// Get collection from database
+	collection, err := db.GetDBCollection("ConstraintProfiles")
+
+	if err != nil {
+		fmt.Println(err)
+		return errors.New("unable to fetch collection from database")
+	}
+
+	profile.UpdatedAt = time.Now().Unix()
+
+	opts := options.Replace().SetUpsert(true)
+	_, err = collection.ReplaceOne(context.TODO(), bson.M{"userid": profile.UserId, "name": profile.Name}, profile, opts)
+
+	if err != nil {
+		return errors.New("unable to save constraint profile")
+	}
+
+	return nil
+}
+
+// Removes a constraint profile of a user
+func deleteConstraintProfile(userId string, name string) error {
+	// Get collection from database
+	collection, err := db.GetDBCollection("ConstraintProfiles")
+
+	if err != nil {
+		fmt.Println(err)
+		return errors.New("unable to fetch collection from database")
+	}
+
+	result, err := collection.DeleteOne(context.TODO(), bson.M{"userid": userId, "name": name})
+	if err != nil {
+		return errors.New("unable to delete constraint profile")
+	}
+
+	if result.DeletedCount == 0 {
+		return errors.New("did not find specified profile")
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+	"reflect"
+	"testing"
+)
+
+func TestConstraintsBSON(t *testing.T) {
+	constraints := UserScheduleConstraints{
+		SchoolId:             "pcc",
+		InstructionalMethods: []InstructionalMethod{MethodOnline, MethodInPerson},
+		Availability:         []AvailabilityStatus{AvailabilityOpen, AvailabilityWaitlisted},
+	}
+
+	data, err := bson.Marshal(constraints)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Stored by name so documents do not depend on the order of the constants
+	var raw struct {
+		InstructionalMethods []string
+		Availability         []string
+	}
+	if err := bson.Unmarshal(data, &raw); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(raw.InstructionalMethods, []string{"online", "inPerson"}) || !reflect.DeepEqual(raw.Availability, []string{"open", "waitlisted"}) {
+		t.Errorf("stored as %+v", raw)
+	}
+
+	var decoded UserScheduleConstraints
+	if err := bson.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded.InstructionalMethods, constraints.InstructionalMethods) || !reflect.DeepEqual(decoded.Availability, constraints.Availability) {
+		t.Errorf("decoded %v %v, want %v %v", decoded.InstructionalMethods, decoded.Availability, constraints.InstructionalMethods, constraints.Availability)
+	}
+}
+
+func TestConstraintsBSONStoredAsNumbers(t *testing.T) {
+	data, err := bson.Marshal(bson.M{
+		"instructionalmethods": bson.A{int32(MethodHybrid)},
+		"availability":         bson.A{int64(AvailabilityClosed)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var constraints UserScheduleConstraints
+	if err := bson.Unmarshal(data, &constraints); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(constraints.InstructionalMethods, []InstructionalMethod{MethodHybrid}) || !reflect.DeepEqual(constraints.Availability, []AvailabilityStatus{AvailabilityClosed}) {
+		t.Errorf("decoded %v %v", constraints.InstructionalMethods, constraints.Availability)
+	}
+
+	data, err = bson.Marshal(bson.M{"availability": bson.A{"sold out"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := bson.Unmarshal(data, &constraints); err == nil {
+		t.Error("an unknown availability was decoded without an error")
+	}
+}
@@ -0,0 +1,306 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// NumericRating is a rate my professor rating, -1 when the professor has none
+// Older professor exports stored ratings as strings ("4.5", "N/A"), so both strings and numbers are decoded
+type NumericRating float32
+
+// Parses a rating from a string, anything that is not a number (e.g. "N/A") is no rating
+func parseNumericRating(value string) NumericRating {
+	rating, err := strconv.ParseFloat(strings.TrimSpace(value), 32)
+	if err != nil {
+		return -1
+	}
+
+	return NumericRating(rating)
+}
+
+func (rating *NumericRating) UnmarshalJSON(data []byte) error {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	switch value := value.(type) {
+	case float64:
+		*rating = NumericRating(value)
+	case string:
+		*rating = parseNumericRating(value)
+	case nil:
+		*rating = -1
+	default:
+		return fmt.Errorf("invalid rating %s", string(data))
+	}
+
+	return nil
+}
+
+func (rating *NumericRating) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	ok := true
+
+	switch t {
+	case bsontype.Double:
+		var value float64
+		value, _, ok = bsoncore.ReadDouble(data)
+		*rating = NumericRating(value)
+	case bsontype.Int32:
+		var value int32
+		value, _, ok = bsoncore.ReadInt32(data)
+		*rating = NumericRating(value)
+	case bsontype.Int64:
+		var value int64
+		value, _, ok = bsoncore.ReadInt64(data)
+		*rating = NumericRating(value)
+	case bsontype.String:
+		var value string
+		value, _, ok = bsoncore.ReadString(data)
+		*rating = parseNumericRating(value)
+	case bsontype.Null, bsontype.Undefined:
+		*rating = -1
+	default:
+		return fmt.Errorf("invalid rating of type %s", t)
+	}
+
+	if !ok {
+		return fmt.Errorf("invalid rating of type %s", t)
+	}
+
+	return nil
+}
+
+// Professors exported without an overallRating have no rating, not a rating of 0
+func (professor *ProfessorType) UnmarshalJSON(data []byte) error {
+	type rawProfessor ProfessorType
+	raw := rawProfessor{OverallRating: -1}
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*professor = ProfessorType(raw)
+	return nil
+}
+
+// Professors stored without an overallRating have no rating, not a rating of 0
+func (professor *ProfessorType) UnmarshalBSON(data []byte) error {
+	type rawProfessor ProfessorType
+	raw := rawProfessor{OverallRating: -1}
+
+	if err := bson.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*professor = ProfessorType(raw)
+	return nil
+}
+
+// UnratedPolicy decides what rating a section gets when its instructor could not be matched to a rating
+type UnratedPolicy string
+
+const (
+	UnratedNeutral           UnratedPolicy = "neutral"           // middle of the rating scale (default)
+	UnratedSchoolAverage     UnratedPolicy = "schoolAverage"     // average rating of every professor at the school
+	UnratedDepartmentAverage UnratedPolicy = "departmentAverage" // average rating of the professors in the course's department
+	UnratedExclude           UnratedPolicy = "exclude"           // section is removed
+)
+
+// Middle of the 1 - 5 rate my professor scale
+const neutralRating float32 = 3
+
+// RatingMode decides which instructor rating is used to filter and score sections
+type RatingMode string
+
+const (
+	RatingRaw      RatingMode = "raw"      // the professor's overall rating (default)
+	RatingWeighted RatingMode = "weighted" // the overall rating weighted by how many ratings it is based on
+)
+
+// How many ratings of the school average every professor starts with in the weighted rating
+// A professor needs about this many ratings before their own ratings count as much as the school average
+const ratingPriorWeight = 5
+
+// Bayesian average of a professor's rating, so a 5.0 from one review does not outrank a 4.6 from 200
+func weightedRating(rating float32, totalRatings int, schoolAverage float32) float32 {
+	return (ratingPriorWeight*schoolAverage + float32(totalRatings)*rating) / (ratingPriorWeight + float32(totalRatings))
+}
+
+// Returns the rating of a class used for filtering and scoring, according to the constraints' RatingMode
+func effectiveRating(class ClassEnhanced, constraints UserScheduleConstraints) float32 {
+	if constraints.RatingMode == RatingWeighted {
+		return class.WeightedRating
+	}
+
+	return class.InstructorRating
+}
+
+// Returns the subject of a course code, e.g. "MATH" for "MATH 005A"
+func courseSubject(courseName string) string {
+	end := strings.IndexFunc(courseName, func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	if end == -1 {
+		return strings.ToUpper(courseName)
+	}
+
+	return strings.ToUpper(courseName[:end])
+}
+
+// Returns the overall rating of a professor, false if they do not have one
+func professorRating(professor ProfessorType) (float32, bool) {
+	if professor.TotalRatings == 0 || professor.OverallRating < 0 {
+		return 0, false
+	}
+
+	return float32(professor.OverallRating), true
+}
+
+// Average overall rating of every professor that has been rated
+// Returns neutralRating if nobody has
+func schoolAverageRating(professors []ProfessorType) float32 {
+	var total float32
+	count := 0
+
+	for _, professor := range professors {
+		rating, ok := professorRating(professor)
+		if !ok {
+			continue
+		}
+
+		total += rating
+		count++
+	}
+
+	if count == 0 {
+		return neutralRating
+	}
+
+	return total / float32(count)
+}
+
+// Sum and count of the ratings of the rated professors of a department
+type departmentRating struct {
+	total float32
+	count int
+}
+
+// Adds up the ratings of the rated professors by (normalized) department
+func departmentRatings(professors []ProfessorType) map[string]departmentRating {
+	ratings := map[string]departmentRating{}
+
+	for _, professor := range professors {
+		department := normalizeName(professor.Department)
+		rating, ok := professorRating(professor)
+		if department == "" || !ok {
+			continue
+		}
+
+		sum := ratings[department]
+		sum.total += rating
+		sum.count++
+		ratings[department] = sum
+	}
+
+	return ratings
+}
+
+// Average overall rating of the rated professors in the department of a course, see departmentRatings
+// Returns false if nobody in the department has been rated
+func departmentAverageRating(schoolId string, courseName string, ratings map[string]departmentRating) (float32, bool) {
+	var total float32
+	count := 0
+
+	for department, sum := range ratings {
+		if inCourseDepartment(schoolId, courseName, department) {
+			total += sum.total
+			count += sum.count
+		}
+	}
+
+	if count == 0 {
+		return 0, false
+	}
+
+	return total / float32(count), true
+}
+
+// Gives the classes without an instructor rating a rating according to the constraints' UnratedPolicy
+// Locked classes are never excluded
+func applyUnratedPolicy(classes []ClassEnhanced, professors []ProfessorType, constraints UserScheduleConstraints) []ClassEnhanced {
+	newClasses := []ClassEnhanced{}
+
+	schoolAverage := schoolAverageRating(professors)
+
+	var departments map[string]departmentRating
+	if constraints.UnratedPolicy == UnratedDepartmentAverage {
+		departments = departmentRatings(professors)
+	}
+
+	for _, class := range classes {
+		if class.Rated {
+			newClasses = append(newClasses, class)
+			continue
+		}
+
+		switch constraints.UnratedPolicy {
+		case UnratedExclude:
+			if !classIDInList(class.ClassID, constraints.LockedClassIDs) {
+				continue
+			}
+			class.InstructorRating = neutralRating
+		case UnratedSchoolAverage:
+			class.InstructorRating = schoolAverage
+		case UnratedDepartmentAverage:
+			if average, ok := departmentAverageRating(constraints.SchoolId, class.CourseName, departments); ok {
+				class.InstructorRating = average
+			} else {
+				class.InstructorRating = schoolAverage
+			}
+		default:
+			class.InstructorRating = neutralRating
+		}
+		class.WeightedRating = class.InstructorRating
+
+		newClasses = append(newClasses, class)
+	}
+
+	return newClasses
+}
+
+// Removes the classes rated below the constraints' MinRating
+// Locked classes are always kept
+func filterByMinRating(classes []ClassEnhanced, constraints UserScheduleConstraints) []ClassEnhanced {
+	if constraints.MinRating <= 0 {
+		return classes
+	}
+
+	newClasses := []ClassEnhanced{}
+
+	for _, class := range classes {
+		if effectiveRating(class, constraints) < constraints.MinRating && !classIDInList(class.ClassID, constraints.LockedClassIDs) {
+			continue
+		}
+
+		newClasses = append(newClasses, class)
+	}
+
+	return newClasses
+}
+
+// Rates classes with the professors of a ProfessorExport (or the professors their instructors are aliased to),
+// then applies the constraints' UnratedPolicy and MinRating, leaving out the classes that do not meet them
+func rateClasses(schoolId string, classes []Class, professorsExport ProfessorExport, aliases []InstructorAlias, constraints UserScheduleConstraints) []ClassEnhanced {
+	enhancedClasses := integrateRatingsIntoClassData(schoolId, classes, professorIndexFor(professorsExport), indexInstructorAliases(aliases))
+
+	enhancedClasses = applyUnratedPolicy(enhancedClasses, professorsExport.Professors, constraints)
+	return filterByMinRating(enhancedClasses, constraints)
+}
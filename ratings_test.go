@@ -0,0 +1,211 @@
+package main
+
+import (
+	"encoding/json"
+	"go.mongodb.org/mongo-driver/bson"
+	"math"
+	"testing"
+)
+
+func TestNumericRatingJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want NumericRating
+	}{
+		{"number", `{"overallRating": 4.5}`, 4.5},
+		{"string", `{"overallRating": "3.5"}`, 3.5},
+		{"not a number", `{"overallRating": "N/A"}`, -1},
+		{"null", `{"overallRating": null}`, -1},
+		{"missing", `{"lastName": "Smith"}`, -1},
+	}
+
+	for _, test := range tests {
+		var professor ProfessorType
+		if err := json.Unmarshal([]byte(test.json), &professor); err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if professor.OverallRating != test.want {
+			t.Errorf("%s: rating = %v, want %v", test.name, professor.OverallRating, test.want)
+		}
+	}
+
+	var professor ProfessorType
+	if err := json.Unmarshal([]byte(`{"overallRating": true}`), &professor); err == nil {
+		t.Error("a boolean rating was decoded without an error")
+	}
+}
+
+func TestNumericRatingBSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		document bson.M
+		want     NumericRating
+	}{
+		{"double", bson.M{"overallrating": 4.5}, 4.5},
+		{"int32", bson.M{"overallrating": int32(4)}, 4},
+		{"int64", bson.M{"overallrating": int64(2)}, 2},
+		{"string", bson.M{"overallrating": "3.5"}, 3.5},
+		{"not a number", bson.M{"overallrating": "N/A"}, -1},
+		{"null", bson.M{"overallrating": nil}, -1},
+		{"missing", bson.M{"lastname": "Smith"}, -1},
+	}
+
+	for _, test := range tests {
+		data, err := bson.Marshal(bson.M{"professors": bson.A{test.document}})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var export ProfessorExport
+		if err := bson.Unmarshal(data, &export); err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if len(export.Professors) != 1 || export.Professors[0].OverallRating != test.want {
+			t.Errorf("%s: professors = %+v, want a rating of %v", test.name, export.Professors, test.want)
+		}
+	}
+}
+
+func TestApplyUnratedPolicy(t *testing.T) {
+	professors := []ProfessorType{
+		{FirstName: "John", LastName: "Smith", Department: "Mathematics", OverallRating: 4, TotalRatings: 10},
+		{FirstName: "Ann", LastName: "Lee", Department: "Mathematics", OverallRating: 5, TotalRatings: 10},
+		{FirstName: "Jane", LastName: "Doe", Department: "English", OverallRating: 2, TotalRatings: 10},
+		{FirstName: "Bob", LastName: "Brown", Department: "Mathematics", OverallRating: -1},
+	}
+
+	rated := ClassEnhanced{ClassID: "100", CourseName: "MATH 5A", InstructorRating: 3.2, Rated: true}
+	unratedMath := ClassEnhanced{ClassID: "200", CourseName: "MATH 5A", InstructorRating: -1}
+	unratedHistory := ClassEnhanced{ClassID: "300", CourseName: "HIST 7B", InstructorRating: -1}
+	classes := []ClassEnhanced{rated, unratedMath, unratedHistory}
+
+	tests := []struct {
+		name   string
+		policy UnratedPolicy
+		locked []string
+		want   map[string]float32 // class id -> rating, classes not in the map are removed
+	}{
+		{"neutral by default", "", nil, map[string]float32{"100": 3.2, "200": 3, "300": 3}},
+		{"school average", UnratedSchoolAverage, nil, map[string]float32{"100": 3.2, "200": 11.0 / 3, "300": 11.0 / 3}},
+		{"department average, school average without a rated department", UnratedDepartmentAverage, nil, map[string]float32{"100": 3.2, "200": 4.5, "300": 11.0 / 3}},
+		{"exclude", UnratedExclude, nil, map[string]float32{"100": 3.2}},
+		{"exclude keeps locked sections", UnratedExclude, []string{"300"}, map[string]float32{"100": 3.2, "300": 3}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			constraints := UserScheduleConstraints{SchoolId: "pcc", UnratedPolicy: test.policy, LockedClassIDs: test.locked}
+			got := applyUnratedPolicy(classes, professors, constraints)
+
+			if len(got) != len(test.want) {
+				t.Fatalf("got %d classes, want %d", len(got), len(test.want))
+			}
+			for _, class := range got {
+				want, ok := test.want[class.ClassID]
+				if !ok {
+					t.Errorf("class %s was kept", class.ClassID)
+					continue
+				}
+				if math.Abs(float64(class.InstructorRating-want)) > 0.0001 {
+					t.Errorf("class %s rating = %.4f, want %.4f", class.ClassID, class.InstructorRating, want)
+				}
+				if class.Rated != (class.ClassID == "100") {
+					t.Errorf("class %s Rated = %v", class.ClassID, class.Rated)
+				}
+			}
+		})
+	}
+}
+
+func TestFilterByMinRating(t *testing.T) {
+	classes := []ClassEnhanced{
+		{ClassID: "100", InstructorRating: 4.5, WeightedRating: 3.8},
+		{ClassID: "200", InstructorRating: 3.4, WeightedRating: 3.6},
+		{ClassID: "300", InstructorRating: 2, WeightedRating: 2},
+	}
+
+	tests := []struct {
+		name        string
+		constraints UserScheduleConstraints
+		want        []string
+	}{
+		{"no minimum", UserScheduleConstraints{}, []string{"100", "200", "300"}},
+		{"raw rating", UserScheduleConstraints{MinRating: 3.5}, []string{"100"}},
+		{"weighted rating", UserScheduleConstraints{MinRating: 3.5, RatingMode: RatingWeighted}, []string{"100", "200"}},
+		{"locked sections are kept", UserScheduleConstraints{MinRating: 3.5, LockedClassIDs: []string{"300"}}, []string{"100", "300"}},
+	}
+
+	for _, test := range tests {
+		got := []string{}
+		for _, class := range filterByMinRating(classes, test.constraints) {
+			got = append(got, class.ClassID)
+		}
+
+		if len(got) != len(test.want) {
+			t.Errorf("%s: kept %v, want %v", test.name, got, test.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != test.want[i] {
+				t.Errorf("%s: kept %v, want %v", test.name, got, test.want)
+				break
+			}
+		}
+	}
+}
+
+func TestWeightedRating(t *testing.T) {
+	tests := []struct {
+		rating        float32
+		totalRatings  int
+		schoolAverage float32
+		want          float32
+	}{
+		{5, 0, 3.5, 3.5},
+		{5, 5, 3, 4},
+		{4, 95, 3, 3.95},
+		{2, 5, 4, 3},
+	}
+
+	for _, test := range tests {
+		if got := weightedRating(test.rating, test.totalRatings, test.schoolAverage); math.Abs(float64(got-test.want)) > 0.0001 {
+			t.Errorf("weightedRating(%v, %d, %v) = %v, want %v", test.rating, test.totalRatings, test.schoolAverage, got, test.want)
+		}
+	}
+}
+
+func TestIntegrateRatingsMetrics(t *testing.T) {
+	wouldTakeAgain := float32(80)
+	professors := []ProfessorType{
+		{Id: 1, FirstName: "John", LastName: "Smith", OverallRating: 5, TotalRatings: 3, AvgDifficulty: 2.5, WouldTakeAgainPercent: &wouldTakeAgain},
+		{Id: 2, FirstName: "Jane", LastName: "Doe", OverallRating: 4, TotalRatings: 100},
+	}
+	classes := []Class{
+		{ClassID: "100", CourseName: "MATH 5A", Instructor: "John Smith"},
+		{ClassID: "200", CourseName: "ENGL 1A", Instructor: "Jane Doe"},
+	}
+
+	enhanced := integrateRatingsIntoClassData("pcc", classes, newProfessorIndex(professors), nil)
+	few, many := enhanced[0], enhanced[1]
+
+	if few.RatingCount != 3 || few.Difficulty != 2.5 || few.WouldTakeAgain != 80 {
+		t.Errorf("section 100 metrics = %d ratings, difficulty %v, would take again %v", few.RatingCount, few.Difficulty, few.WouldTakeAgain)
+	}
+	if many.RatingCount != 100 || many.Difficulty != 0 || many.WouldTakeAgain != -1 {
+		t.Errorf("section 200 metrics = %d ratings, difficulty %v, would take again %v", many.RatingCount, many.Difficulty, many.WouldTakeAgain)
+	}
+
+	// The school average is 4.5, three ratings are pulled towards it much more than a hundred ratings are
+	if math.Abs(float64(few.WeightedRating-4.6875)) > 0.0001 || math.Abs(float64(many.WeightedRating-422.5/105)) > 0.0001 {
+		t.Errorf("weighted ratings = %v, %v, want 4.6875, %v", few.WeightedRating, many.WeightedRating, 422.5/105)
+	}
+
+	raw := UserScheduleConstraints{}
+	weighted := UserScheduleConstraints{RatingMode: RatingWeighted}
+	if effectiveRating(few, raw) != 5 || effectiveRating(few, weighted) != few.WeightedRating {
+		t.Errorf("effective ratings = %v raw, %v weighted", effectiveRating(few, raw), effectiveRating(few, weighted))
+	}
+}
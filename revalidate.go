@@ -0,0 +1,334 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Number of replacement sections suggested for every section that no longer works
+const replacementLimit = 3
+
+// ScheduleIssueKind is what happened to a section of a saved schedule since it was picked
+type ScheduleIssueKind string
+
+const (
+	ScheduleSectionCancelled  ScheduleIssueKind = "cancelled"         // not in the class data anymore
+	ScheduleSectionMoved      ScheduleIssueKind = "moved"             // meeting times or dates changed
+	ScheduleSectionFilled     ScheduleIssueKind = "filled"            // closed, or waitlisted when it used to be open
+	ScheduleInstructorChanged ScheduleIssueKind = "instructorChanged" // taught by someone else
+	ScheduleSectionConflicts  ScheduleIssueKind = "conflicts"         // overlaps another section of the schedule
+	ScheduleConstraintsNotMet ScheduleIssueKind = "constraintsNotMet" // removed by the schedule constraints
+)
+
+type ScheduleIssue struct {
+	ClassID    string            `json:"classID"`
+	CourseName string            `json:"courseName"`
+	Kind       ScheduleIssueKind `json:"kind"`
+	Message    string            `json:"message"`
+}
+
+// ReplacementSuggestion is the sections that could take the place of a section that no longer works, nearest first
+type ReplacementSuggestion struct {
+	ClassID      string  `json:"classID"`
+	CourseName   string  `json:"courseName"`
+	Replacements []Class `json:"replacements"`
+}
+
+// ScheduleRevalidation is the result of checking a saved schedule against newer class data
+type ScheduleRevalidation struct {
+	Timestamp    int64                   `json:"timestamp"` // timestamp of the class data checked against
+	Valid        bool                    `json:"valid"`     // false if any section needs to be replaced
+	Issues       []ScheduleIssue         `json:"issues"`
+	Replacements []ReplacementSuggestion `json:"replacements"`
+}
+
+// Checks the sections of a saved schedule against the current class data of the school:
+// the sections must still exist, have seats, fit the constraints (rating ones included) and not conflict with each other
+// previous is the class data the schedule was made with, when given it is also reported which sections moved or changed instructor
+// Both schools' instructional methods must already be resolved (see normalizeInstructionalMethods)
+func revalidateSchedule(classIDs []string, current School, previous *School, professorsExport ProfessorExport, aliases []InstructorAlias, constraints UserScheduleConstraints) ScheduleRevalidation {
+	revalidation := ScheduleRevalidation{
+		Timestamp:    current.Timestamp,
+		Valid:        true,
+		Issues:       []ScheduleIssue{},
+		Replacements: []ReplacementSuggestion{},
+	}
+
+	issue := func(class Class, kind ScheduleIssueKind, message string) {
+		revalidation.Issues = append(revalidation.Issues, ScheduleIssue{class.ClassID, class.CourseName, kind, message})
+	}
+
+	currentClasses := map[string]Class{}
+	for _, class := range current.Classes {
+		currentClasses[class.ClassID] = class
+	}
+
+	previousClasses := map[string]Class{}
+	if previous != nil {
+		for _, class := range previous.Classes {
+			previousClasses[class.ClassID] = class
+		}
+	}
+
+	// Saved sections are not re-picked, so their locks do not matter here
+	unlocked := constraints
+	unlocked.LockedClassIDs = nil
+	courseCodes := requestedCourseCodes(constraints)
+
+	// Saved sections still offered that meet the rating constraints
+	offered := []Class{}
+	for _, classID := range classIDs {
+		if class, ok := currentClasses[classID]; ok {
+			offered = append(offered, class)
+		}
+	}
+	rated := map[string]bool{}
+	for _, class := range rateClasses(constraints.SchoolId, offered, professorsExport, aliases, unlocked) {
+		rated[class.ClassID] = true
+	}
+
+	scheduled := []Class{}
+	original := map[string]Class{} // each section as the schedule was made with it
+	replace := map[string]bool{}
+	moved := map[string]bool{}
+
+	for _, classID := range classIDs {
+		previousClass, known := previousClasses[classID]
+
+		class, ok := currentClasses[classID]
+		if !ok {
+			if known {
+				code := normalizeCourseCode(previousClass.CourseName)
+				if requestedCourse, ok := courseCodes[code]; ok && normalizeCourseCode(requestedCourse) != code {
+					previousClass.RequestedCourse = requestedCourse
+				}
+				issue(previousClass, ScheduleSectionCancelled, "section is no longer offered")
+				scheduled = append(scheduled, previousClass)
+				original[classID] = previousClass
+			} else {
+				issue(Class{ClassID: classID}, ScheduleSectionCancelled, "section is no longer offered")
+			}
+			replace[classID] = true
+			continue
+		}
+
+		code := normalizeCourseCode(class.CourseName)
+		if requestedCourse, ok := courseCodes[code]; ok && normalizeCourseCode(requestedCourse) != code {
+			class.RequestedCourse = requestedCourse
+		}
+		original[classID] = class
+
+		if known {
+			previousClass.RequestedCourse = class.RequestedCourse
+			original[classID] = previousClass
+
+			for _, change := range diffSection(previousClass, class) {
+				switch change.Kind {
+				case SectionTimeChanged:
+					issue(class, ScheduleSectionMoved, fmt.Sprintf("moved from %s to %s", change.Before, change.After))
+					moved[classID] = true
+				case SectionInstructorChanged:
+					issue(class, ScheduleInstructorChanged, fmt.Sprintf("instructor changed from %s to %s", change.Before, change.After))
+				}
+			}
+		}
+
+		status := class.AvailabilityStatus()
+		if status == AvailabilityClosed || (known && status == AvailabilityWaitlisted && previousClass.AvailabilityStatus() == AvailabilityOpen) {
+			issue(class, ScheduleSectionFilled, fmt.Sprintf("section is %s", status))
+			replace[classID] = true
+		}
+
+		if len(getClassesThatFitScheduleConstraints([]Class{class}, unlocked)) == 0 {
+			issue(class, ScheduleConstraintsNotMet, "section no longer fits the schedule constraints")
+			replace[classID] = true
+		} else if !rated[classID] {
+			issue(class, ScheduleConstraintsNotMet, "instructor rating no longer meets the rating constraints")
+			replace[classID] = true
+		}
+
+		scheduled = append(scheduled, class)
+	}
+
+	// Sections that still exist but now overlap, when only one of a pair moved it is the one to replace
+	for i, class := range scheduled {
+		if _, ok := currentClasses[class.ClassID]; !ok {
+			continue
+		}
+
+		for _, other := range scheduled[i+1:] {
+			if _, ok := currentClasses[other.ClassID]; !ok {
+				continue
+			}
+			if isScheduleValid([]ClassEnhanced{enhanceClass(class, -1), enhanceClass(other, -1)}) {
+				continue
+			}
+
+			issue(class, ScheduleSectionConflicts, fmt.Sprintf("conflicts with %s (%s)", other.ClassID, other.CourseName))
+			issue(other, ScheduleSectionConflicts, fmt.Sprintf("conflicts with %s (%s)", class.ClassID, class.CourseName))
+
+			if moved[class.ClassID] == moved[other.ClassID] {
+				replace[class.ClassID] = true
+				replace[other.ClassID] = true
+			} else if moved[class.ClassID] {
+				replace[class.ClassID] = true
+			} else {
+				replace[other.ClassID] = true
+			}
+		}
+	}
+
+	// Sections staying in the schedule, replacements must not conflict with them
+	kept := []ClassEnhanced{}
+	for _, class := range scheduled {
+		if !replace[class.ClassID] {
+			kept = append(kept, enhanceClass(class, -1))
+		}
+	}
+
+	for _, classID := range classIDs {
+		if !replace[classID] {
+			continue
+		}
+		revalidation.Valid = false
+
+		// Without the old class data we do not know which course a cancelled section was for
+		class, ok := original[classID]
+		if !ok {
+			continue
+		}
+
+		revalidation.Replacements = append(revalidation.Replacements, ReplacementSuggestion{
+			ClassID:      classID,
+			CourseName:   class.CourseName,
+			Replacements: suggestReplacements(class, classIDs, kept, current, professorsExport, aliases, unlocked, courseCodes),
+		})
+	}
+
+	return revalidation
+}
+
+// Returns up to replacementLimit sections of the same (requested) course that fit the constraints (rating ones included),
+// have seats and do not conflict with the kept sections, the ones meeting closest to the original section first
+func suggestReplacements(original Class, classIDs []string, kept []ClassEnhanced, current School, professorsExport ProfessorExport, aliases []InstructorAlias, constraints UserScheduleConstraints, courseCodes map[string]string) []Class {
+	candidates := []Class{}
+
+	for _, class := range current.Classes {
+		if classIDInList(class.ClassID, classIDs) {
+			continue
+		}
+
+		code := normalizeCourseCode(class.CourseName)
+		if requestedCourse, ok := courseCodes[code]; ok && normalizeCourseCode(requestedCourse) != code {
+			class.RequestedCourse = requestedCourse
+		}
+		if class.requestedCourseCode() != original.requestedCourseCode() {
+			continue
+		}
+
+		if class.AvailabilityStatus() == AvailabilityClosed {
+			continue
+		}
+		if len(getClassesThatFitScheduleConstraints([]Class{class}, constraints)) == 0 {
+			continue
+		}
+		if !isScheduleValid(append(kept[:len(kept):len(kept)], enhanceClass(class, -1))) {
+			continue
+		}
+
+		candidates = append(candidates, class)
+	}
+
+	rated := map[string]bool{}
+	for _, class := range rateClasses(constraints.SchoolId, candidates, professorsExport, aliases, constraints) {
+		rated[class.ClassID] = true
+	}
+	ratedCandidates := []Class{}
+	for _, class := range candidates {
+		if rated[class.ClassID] {
+			ratedCandidates = append(ratedCandidates, class)
+		}
+	}
+	candidates = ratedCandidates
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		distanceI := sectionTimeDistance(original, candidates[i])
+		distanceJ := sectionTimeDistance(original, candidates[j])
+		if distanceI != distanceJ {
+			return distanceI < distanceJ
+		}
+
+		// Same instructor as before, then more open seats
+		sameI := normalizeName(candidates[i].Instructor) == normalizeName(original.Instructor)
+		sameJ := normalizeName(candidates[j].Instructor) == normalizeName(original.Instructor)
+		if sameI != sameJ {
+			return sameI
+		}
+
+		return candidates[i].OpenSeats() > candidates[j].OpenSeats()
+	})
+
+	if len(candidates) > replacementLimit {
+		candidates = candidates[:replacementLimit]
+	}
+
+	return candidates
+}
+
+// How far apart two sections meet, in minutes between their first start times plus an hour for every day only one of them meets on
+// Sections without meeting times are only close to each other
+func sectionTimeDistance(class1 Class, class2 Class) int {
+	if len(class1.MeetingTimes) == 0 || len(class2.MeetingTimes) == 0 {
+		if len(class1.MeetingTimes) == len(class2.MeetingTimes) {
+			return 0
+		}
+		return 24 * 60
+	}
+
+	distance := timeInMinutes(class1.MeetingTimes[0].StartTime) - timeInMinutes(class2.MeetingTimes[0].StartTime)
+	if distance < 0 {
+		distance = -distance
+	}
+
+	days1 := meetingDays(class1.MeetingTimes[0])
+	days2 := meetingDays(class2.MeetingTimes[0])
+	for i := range days1 {
+		if days1[i] != days2[i] {
+			distance += 60
+		}
+	}
+
+	return distance
+}
+
+// Revalidates a saved schedule against the latest class data of the constraints' school
+// since is the timestamp of the class data the schedule was made with, 0 if it is not known
+func revalidateSavedSchedule(classIDs []string, constraints UserScheduleConstraints, since int64) (ScheduleRevalidation, error) {
+	current, err := fetchClassData(constraints.SchoolId)
+	if err != nil {
+		return ScheduleRevalidation{}, err
+	}
+	warnUnknownInstructionalMethods(normalizeInstructionalMethods(&current, constraints.SchoolId))
+
+	var previous *School
+	if since != 0 && since != current.Timestamp {
+		snapshot, err := fetchClassDataSnapshot(constraints.SchoolId, since)
+		if err != nil {
+			return ScheduleRevalidation{}, err
+		}
+		warnUnknownInstructionalMethods(normalizeInstructionalMethods(&snapshot, constraints.SchoolId))
+		previous = &snapshot
+	}
+
+	professorsExport, err := fetchProfessorData(constraints.SchoolId)
+	if err != nil {
+		return ScheduleRevalidation{}, err
+	}
+
+	aliases, err := fetchInstructorAliases(constraints.SchoolId)
+	if err != nil {
+		return ScheduleRevalidation{}, err
+	}
+
+	return revalidateSchedule(classIDs, current, previous, professorsExport, aliases, constraints), nil
+}
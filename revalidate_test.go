@@ -0,0 +1,73 @@
+package main
+
+import (
+	"testing"
+)
+
+// Constraints of a student free every weekday who takes in person MATH 5A sections rated 3.5 or more
+func revalidationConstraints() UserScheduleConstraints {
+	allDay := []TimeRange{{StartTime: Time{0, 0}, EndTime: Time{23, 59}}}
+
+	return UserScheduleConstraints{
+		SchoolId:             "pcc",
+		Courses:              []string{"MATH 5A"},
+		MondayTime:           allDay,
+		TuesdayTime:          allDay,
+		WednesdayTime:        allDay,
+		ThursdayTime:         allDay,
+		FridayTime:           allDay,
+		InstructionalMethods: []InstructionalMethod{MethodInPerson},
+		Availability:         []AvailabilityStatus{AvailabilityOpen, AvailabilityWaitlisted},
+		UnratedPolicy:        UnratedExclude,
+		MinRating:            3.5,
+	}
+}
+
+func TestRevalidateScheduleRatings(t *testing.T) {
+	professors := ProfessorExport{SchoolId: "pcc", Timestamp: -1, Professors: []ProfessorType{
+		{Id: 1, FirstName: "John", LastName: "Smith", OverallRating: 2.5, TotalRatings: 20},
+		{Id: 2, FirstName: "Jane", LastName: "Doe", OverallRating: 4.5, TotalRatings: 20},
+		{Id: 3, FirstName: "Ann", LastName: "Lee", OverallRating: 3, TotalRatings: 20},
+	}}
+
+	current := School{Timestamp: 2, Classes: []Class{
+		testSection("100", "MATH 5A", "John Smith", 8),   // saved, now rated below the minimum
+		testSection("200", "MATH 5A", "Ann Lee", 9),      // rated below the minimum
+		testSection("300", "MATH 5A", "Nobody Known", 9), // unrated, excluded by the policy
+		testSection("400", "MATH 5A", "Jane Doe", 12),    // the only replacement meeting the rating constraints
+	}}
+
+	revalidation := revalidateSchedule([]string{"100"}, current, nil, professors, nil, revalidationConstraints())
+
+	if revalidation.Valid {
+		t.Fatal("schedule with a section below the minimum rating is valid")
+	}
+
+	if len(revalidation.Issues) != 1 || revalidation.Issues[0].ClassID != "100" || revalidation.Issues[0].Kind != ScheduleConstraintsNotMet {
+		t.Errorf("issues = %+v, want section 100 not meeting the constraints", revalidation.Issues)
+	}
+
+	if len(revalidation.Replacements) != 1 {
+		t.Fatalf("replacements = %+v, want one suggestion", revalidation.Replacements)
+	}
+	replacements := revalidation.Replacements[0].Replacements
+	if len(replacements) != 1 || replacements[0].ClassID != "400" {
+		t.Errorf("replacements of 100 = %+v, want only section 400", replacements)
+	}
+}
+
+func TestRevalidateScheduleRatingsMet(t *testing.T) {
+	professors := ProfessorExport{SchoolId: "pcc", Timestamp: -2, Professors: []ProfessorType{
+		{Id: 2, FirstName: "Jane", LastName: "Doe", OverallRating: 4.5, TotalRatings: 20},
+	}}
+
+	current := School{Timestamp: 2, Classes: []Class{
+		{ClassID: "400", CourseName: "MATH 5A", Instructor: "Jane Doe", Method: MethodInPerson, Capacity: 30, Enrolled: 10},
+	}}
+
+	revalidation := revalidateSchedule([]string{"400"}, current, nil, professors, nil, revalidationConstraints())
+
+	if !revalidation.Valid || len(revalidation.Issues) != 0 {
+		t.Errorf("revalidation = %+v, want a valid schedule without issues", revalidation)
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+)
+
+// SchoolConfig is everything we need to know about a school we generate schedules for
+type SchoolConfig struct {
+	ID             string `json:"id"`             // our identifier for the school, used as UserScheduleConstraints.SchoolId
+	Name           string `json:"name"`           // display name
+	ClassDataKey   string `json:"classDataKey"`   // schoolid of the school's documents in the Classes collection
+	RatingSourceID string `json:"ratingSourceId"` // rate my professor school id, schoolid of the school's documents in the Professors collection
+	Timezone       string `json:"timezone"`       // IANA timezone the meeting times are in
+
+	Terms []TermCalendar `json:"terms"` // known term calendars, may be empty
+
+	InstructionalMethodCodes map[string]InstructionalMethod `json:"instructionalMethodCodes"` // raw instructional method codes in the class data
+	SubjectDepartments       map[string][]string            `json:"subjectDepartments"`       // course subject -> rate my professor department names
+	CourseEquivalents        [][]string                     `json:"courseEquivalents"`        // groups of cross-listed or equivalent (e.g. honors) courses
+}
+
+// TermCalendar is the first and last day of instruction of a term, and the days without classes
+type TermCalendar struct {
+	Name     string        `json:"name"`
+	Year     int           `json:"year"` // year the term starts in
+	Date     DateRange     `json:"date"`
+	Holidays []CalendarDay `json:"holidays"`
+}
+
+type CalendarDay struct {
+	Month int `json:"month"`
+	Day   int `json:"day"`
+}
+
+// Every school we support, keyed by our school id
+var schools = map[string]SchoolConfig{
+	"pcc": {
+		ID:             "pcc",
+		Name:           "Pasadena City College",
+		ClassDataKey:   "2649",
+		RatingSourceID: "2649",
+		Timezone:       "America/Los_Angeles",
+		InstructionalMethodCodes: map[string]InstructionalMethod{
+			"IP": MethodInPerson,
+			"HY": MethodHybrid,
+			"FO": MethodOnline,
+		},
+		SubjectDepartments: map[string][]string{
+			"ACCT":  {"Accounting"},
+			"ANTH":  {"Anthropology"},
+			"ART":   {"Art", "Fine Arts"},
+			"ASTR":  {"Astronomy"},
+			"BIOL":  {"Biology"},
+			"BUS":   {"Business"},
+			"CHEM":  {"Chemistry"},
+			"CIS":   {"Computer Information Systems", "Computer Science"},
+			"CS":    {"Computer Science"},
+			"ECON":  {"Economics"},
+			"ENGL":  {"English"},
+			"ENGR":  {"Engineering"},
+			"GEOG":  {"Geography"},
+			"HIST":  {"History"},
+			"KINE":  {"Kinesiology", "Physical Education"},
+			"MATH":  {"Mathematics", "Math"},
+			"MUSIC": {"Music"},
+			"PHIL":  {"Philosophy"},
+			"PHYS":  {"Physics"},
+			"POLSC": {"Political Science"},
+			"PSYCH": {"Psychology"},
+			"SOC":   {"Sociology"},
+			"SPAN":  {"Spanish", "Languages"},
+		},
+	},
+}
+
+// Returns the configuration of a school
+func lookupSchool(schoolId string) (SchoolConfig, error) {
+	config, ok := schools[schoolId]
+	if !ok {
+		return SchoolConfig{}, fmt.Errorf("unknown school %q", schoolId)
+	}
+
+	return config, nil
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLookupSchool(t *testing.T) {
+	config, err := lookupSchool("pcc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.ID != "pcc" || config.ClassDataKey != "2649" || config.RatingSourceID != "2649" {
+		t.Errorf("lookupSchool(pcc) = %+v", config)
+	}
+
+	for _, schoolId := range []string{"", "PCC", "2649", "unknown"} {
+		if _, err := lookupSchool(schoolId); err == nil {
+			t.Errorf("lookupSchool(%q) found a school", schoolId)
+		}
+	}
+}
+
+func TestSchoolConfigs(t *testing.T) {
+	for schoolId, config := range schools {
+		if config.ID != schoolId {
+			t.Errorf("school %s has id %q", schoolId, config.ID)
+		}
+		if config.Name == "" || config.ClassDataKey == "" || config.RatingSourceID == "" {
+			t.Errorf("school %s is missing its name or database keys: %+v", schoolId, config)
+		}
+		if _, err := time.LoadLocation(config.Timezone); err != nil || config.Timezone == "" {
+			t.Errorf("school %s has timezone %q", schoolId, config.Timezone)
+		}
+		if len(config.InstructionalMethodCodes) == 0 {
+			t.Errorf("school %s has no instructional method codes", schoolId)
+		}
+	}
+}
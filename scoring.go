@@ -0,0 +1,162 @@
+package main
+
+import (
+	"sort"
+)
+
+// SectionScore is what a section adds to the score of a schedule
+type SectionScore struct {
+	Rating               float32 `json:"rating"`               // instructor rating used for scoring (raw or weighted)
+	OpenSeats            float32 `json:"openSeats"`            // bonus for open seats
+	InstructorPreference float32 `json:"instructorPreference"` // instructors the user asked for
+	InstructorPenalty    float32 `json:"instructorPenalty"`    // instructors the user asked to avoid, 0 or negative
+	Total                float32 `json:"total"`
+}
+
+// SectionContribution is the score of one section of a schedule
+type SectionContribution struct {
+	ClassID    string       `json:"classID"`
+	CourseName string       `json:"courseName"`
+	Score      SectionScore `json:"score"`
+}
+
+// ScheduleBreakdown explains the score of a schedule and describes its week
+type ScheduleBreakdown struct {
+	Sections             []SectionContribution `json:"sections"`
+	Rating               float32               `json:"rating"`               // sum of the section ratings
+	OpenSeats            float32               `json:"openSeats"`            // sum of the open seat bonuses
+	InstructorPreference float32               `json:"instructorPreference"` // sum of the preferred instructor bonuses
+	Penalties            float32               `json:"penalties"`            // sum of the soft preference penalties, 0 or negative
+	Total                float32               `json:"total"`                // score of the schedule
+
+	IdleMinutes   int  `json:"idleMinutes"`   // time between classes on the same day, over the whole week
+	DaysOnCampus  int  `json:"daysOnCampus"`  // days with at least one meeting that is not online
+	EarliestStart Time `json:"earliestStart"` // earliest start of any meeting
+	LatestEnd     Time `json:"latestEnd"`     // latest end of any meeting
+}
+
+// Breaks down the score of a single class
+func scoreClassBreakdown(class ClassEnhanced, constraints UserScheduleConstraints) SectionScore {
+	score := SectionScore{Rating: effectiveRating(class, constraints)}
+
+	// Prefer sections with more of their seats still open
+	if class.Capacity > 0 {
+		score.OpenSeats = constraints.OpenSeatsWeight * float32(class.OpenSeats()) / float32(class.Capacity)
+	}
+
+	// Instructors the user asked for (or asked to avoid)
+	score.InstructorPreference, score.InstructorPenalty = instructorPreferenceWeights(class, constraints)
+
+	score.Total = score.Rating + score.OpenSeats + score.InstructorPreference + score.InstructorPenalty
+
+	return score
+}
+
+// Scores a single class, higher is better
+func scoreClass(class ClassEnhanced, constraints UserScheduleConstraints) float32 {
+	return scoreClassBreakdown(class, constraints).Total
+}
+
+// Scores each section once, by ClassID, so schedules can be scored without scoring their sections again
+func scoreSections(classes []ClassEnhanced, constraints UserScheduleConstraints) map[string]float32 {
+	scores := make(map[string]float32, len(classes))
+
+	for _, class := range classes {
+		scores[class.ClassID] = scoreClass(class, constraints)
+	}
+
+	return scores
+}
+
+// Scores a whole schedule by adding up the score of each of its classes
+func scoreSchedule(classes []ClassEnhanced, sectionScores map[string]float32) float32 {
+	var score float32
+
+	for _, class := range classes {
+		score += sectionScores[class.ClassID]
+	}
+
+	return score
+}
+
+// Breaks down the score of a whole schedule by section, and works out how its week looks
+func breakdownSchedule(classes []ClassEnhanced, constraints UserScheduleConstraints) ScheduleBreakdown {
+	breakdown := ScheduleBreakdown{Sections: []SectionContribution{}}
+
+	for _, class := range classes {
+		score := scoreClassBreakdown(class, constraints)
+		breakdown.Sections = append(breakdown.Sections, SectionContribution{class.ClassID, class.CourseName, score})
+
+		breakdown.Rating += score.Rating
+		breakdown.OpenSeats += score.OpenSeats
+		breakdown.InstructorPreference += score.InstructorPreference
+		breakdown.Penalties += score.InstructorPenalty
+		breakdown.Total += score.Total
+	}
+
+	type Meeting struct {
+		start int
+		end   int
+	}
+
+	days := make([][]Meeting, len(meetingDayLetters))
+	onCampus := make([]bool, len(meetingDayLetters))
+	earliest, latest := -1, -1
+
+	for _, class := range classes {
+		for _, meetingTime := range class.MeetingTimes {
+			start := timeInMinutes(meetingTime.StartTime)
+			end := timeInMinutes(meetingTime.EndTime)
+
+			for day, meets := range meetingDays(meetingTime) {
+				if !meets {
+					continue
+				}
+
+				days[day] = append(days[day], Meeting{start, end})
+				if class.InstructionalMethod != MethodOnline {
+					onCampus[day] = true
+				}
+			}
+
+			if earliest == -1 || start < earliest {
+				earliest = start
+			}
+			if end > latest {
+				latest = end
+			}
+		}
+	}
+
+	for day, meetings := range days {
+		if onCampus[day] {
+			breakdown.DaysOnCampus++
+		}
+
+		// Gaps between one meeting ending and the next starting
+		sort.Slice(meetings, func(i, j int) bool {
+			return meetings[i].start < meetings[j].start
+		})
+		for i := 1; i < len(meetings); i++ {
+			if gap := meetings[i].start - meetings[i-1].end; gap > 0 {
+				breakdown.IdleMinutes += gap
+			}
+		}
+	}
+
+	if earliest != -1 {
+		breakdown.EarliestStart = Time{earliest / 60, earliest % 60}
+		breakdown.LatestEnd = Time{latest / 60, latest % 60}
+	}
+
+	return breakdown
+}
+
+// Explains the rating of each schedule, only the schedules that are shown need it
+func breakdownSchedules(schedules []Schedule, constraints UserScheduleConstraints) []Schedule {
+	for i := range schedules {
+		schedules[i].Breakdown = breakdownSchedule(schedules[i].Classes, constraints)
+	}
+
+	return schedules
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func scoringTestClasses() []ClassEnhanced {
+	return []ClassEnhanced{
+		{ClassID: "100", CourseName: "MATH 5A", Instructor: "John Smith", InstructorRating: 4, WeightedRating: 3.5,
+			Capacity: 40, Enrolled: 30, InstructionalMethod: MethodInPerson,
+			MeetingTimes: []MeetingTime{testMeeting("MW", Time{8, 0}, Time{9, 0})}},
+		{ClassID: "200", CourseName: "ENGL 1A", Instructor: "Jane Doe", InstructorRating: 3, WeightedRating: 3,
+			Capacity: 30, Enrolled: 30, InstructionalMethod: MethodInPerson,
+			MeetingTimes: []MeetingTime{testMeeting("M", Time{11, 10}, Time{12, 30})}},
+		{ClassID: "300", CourseName: "HIST 7B", Instructor: "Ann Lee", InstructorRating: 5, WeightedRating: 4,
+			InstructionalMethod: MethodOnline,
+			MeetingTimes:        []MeetingTime{testMeeting("F", Time{18, 0}, Time{19, 15})}},
+		{ClassID: "400", CourseName: "ART 10", Instructor: "Bob Brown", InstructorRating: 2, WeightedRating: 2,
+			InstructionalMethod: MethodOnline},
+	}
+}
+
+func TestScoreClassBreakdown(t *testing.T) {
+	constraints := UserScheduleConstraints{
+		OpenSeatsWeight: 2,
+		InstructorPreferences: []InstructorPreference{
+			{Instructor: "John Smith", Weight: 1.5},
+			{CourseName: "MATH 5A", Instructor: "Smith", Weight: 0.5},
+			{CourseName: "ENGL 1A", Instructor: "John Smith", Weight: 10}, // other course
+			{Instructor: "Jane Doe", Weight: -2},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		class       ClassEnhanced
+		constraints UserScheduleConstraints
+		want        SectionScore
+	}{
+		{"rating only", scoringTestClasses()[0], UserScheduleConstraints{}, SectionScore{Rating: 4, Total: 4}},
+		{"weighted rating", scoringTestClasses()[0], UserScheduleConstraints{RatingMode: RatingWeighted}, SectionScore{Rating: 3.5, Total: 3.5}},
+		{"open seats and preferences", scoringTestClasses()[0], constraints,
+			SectionScore{Rating: 4, OpenSeats: 0.5, InstructorPreference: 2, Total: 6.5}},
+		{"full section and avoided instructor", scoringTestClasses()[1], constraints,
+			SectionScore{Rating: 3, InstructorPenalty: -2, Total: 1}},
+		{"no seat counts", scoringTestClasses()[2], constraints, SectionScore{Rating: 5, Total: 5}},
+	}
+
+	for _, test := range tests {
+		got := scoreClassBreakdown(test.class, test.constraints)
+		if !closeScores(got, test.want) {
+			t.Errorf("%s: scoreClassBreakdown = %+v, want %+v", test.name, got, test.want)
+		}
+	}
+}
+
+func closeScores(score1 SectionScore, score2 SectionScore) bool {
+	near := func(a float32, b float32) bool {
+		return math.Abs(float64(a-b)) < 0.0001
+	}
+
+	return near(score1.Rating, score2.Rating) && near(score1.OpenSeats, score2.OpenSeats) &&
+		near(score1.InstructorPreference, score2.InstructorPreference) &&
+		near(score1.InstructorPenalty, score2.InstructorPenalty) && near(score1.Total, score2.Total)
+}
+
+func TestBreakdownSchedule(t *testing.T) {
+	constraints := UserScheduleConstraints{
+		OpenSeatsWeight:       2,
+		InstructorPreferences: []InstructorPreference{{Instructor: "Jane Doe", Weight: -1}},
+	}
+	classes := scoringTestClasses()
+
+	breakdown := breakdownSchedule(classes, constraints)
+
+	if len(breakdown.Sections) != len(classes) {
+		t.Fatalf("%d sections, want %d", len(breakdown.Sections), len(classes))
+	}
+	for i, section := range breakdown.Sections {
+		if section.ClassID != classes[i].ClassID || section.CourseName != classes[i].CourseName {
+			t.Errorf("section %d = %s %s, want %s %s", i, section.ClassID, section.CourseName, classes[i].ClassID, classes[i].CourseName)
+		}
+	}
+
+	sums := []struct {
+		name string
+		got  float32
+		want float32
+	}{
+		{"rating", breakdown.Rating, 14},
+		{"open seats", breakdown.OpenSeats, 0.5},
+		{"instructor preference", breakdown.InstructorPreference, 0},
+		{"penalties", breakdown.Penalties, -1},
+		{"total", breakdown.Total, 13.5},
+		{"scoreSchedule", scoreSchedule(classes, scoreSections(classes, constraints)), 13.5},
+	}
+	for _, sum := range sums {
+		if math.Abs(float64(sum.got-sum.want)) > 0.0001 {
+			t.Errorf("%s = %.4f, want %.4f", sum.name, sum.got, sum.want)
+		}
+	}
+
+	// Monday 8:00-9:00 then 11:10-12:30, the online Friday class does not count as a day on campus
+	if breakdown.IdleMinutes != 130 {
+		t.Errorf("IdleMinutes = %d, want 130", breakdown.IdleMinutes)
+	}
+	if breakdown.DaysOnCampus != 2 {
+		t.Errorf("DaysOnCampus = %d, want 2", breakdown.DaysOnCampus)
+	}
+	if breakdown.EarliestStart != (Time{8, 0}) || breakdown.LatestEnd != (Time{19, 15}) {
+		t.Errorf("week = %v - %v, want 8:00 - 19:15", breakdown.EarliestStart, breakdown.LatestEnd)
+	}
+}
+
+func TestBreakdownScheduleWithoutMeetings(t *testing.T) {
+	breakdown := breakdownSchedule(scoringTestClasses()[3:], UserScheduleConstraints{})
+
+	if breakdown.IdleMinutes != 0 || breakdown.DaysOnCampus != 0 || breakdown.EarliestStart != (Time{}) || breakdown.LatestEnd != (Time{}) {
+		t.Errorf("breakdown = %+v, want an empty week", breakdown)
+	}
+	if breakdown.Total != 2 {
+		t.Errorf("Total = %.2f, want 2", breakdown.Total)
+	}
+}
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"schedulegenerator/db"
+	"sort"
+	"strings"
+)
+
+// SectionChangeKind is what changed about a section between two class data snapshots
+type SectionChangeKind string
+
+const (
+	SectionTimeChanged         SectionChangeKind = "timeChanged"         // meeting times or dates changed
+	SectionInstructorChanged   SectionChangeKind = "instructorChanged"   // different instructor
+	SectionAvailabilityChanged SectionChangeKind = "availabilityChanged" // e.g. open -> closed
+)
+
+type SectionChange struct {
+	ClassID    string            `json:"classID"`
+	CourseName string            `json:"courseName"`
+	Kind       SectionChangeKind `json:"kind"`
+	Before     string            `json:"before"`
+	After      string            `json:"after"`
+}
+
+// ClassDataDiff is everything that changed between two class data snapshots of a school
+type ClassDataDiff struct {
+	FromTimestamp int64           `json:"fromTimestamp"`
+	ToTimestamp   int64           `json:"toTimestamp"`
+	Added         []Class         `json:"added"`
+	Removed       []Class         `json:"removed"`
+	Changes       []SectionChange `json:"changes"`
+}
+
+// Day letters in the order they are printed
+var meetingDayLetters = []string{"M", "T", "W", "R", "F", "S", "U"}
+
+// Returns the days of a meeting time, in meetingDayLetters order
+func meetingDays(meetingTime MeetingTime) []bool {
+	return []bool{meetingTime.Monday, meetingTime.Tuesday, meetingTime.Wednesday, meetingTime.Thursday,
+		meetingTime.Friday, meetingTime.Saturday, meetingTime.Sunday}
+}
+
+// Formats meeting times for people, e.g. "MW 08:30-09:55, F 10:00-11:00"
+func formatMeetingTimes(meetingTimes []MeetingTime) string {
+	if len(meetingTimes) == 0 {
+		return "no meeting times"
+	}
+
+	formatted := []string{}
+	for _, meetingTime := range meetingTimes {
+		days := ""
+		for i, meets := range meetingDays(meetingTime) {
+			if meets {
+				days += meetingDayLetters[i]
+			}
+		}
+
+		formatted = append(formatted, fmt.Sprintf("%s %02d:%02d-%02d:%02d", days,
+			meetingTime.StartTime.Hour, meetingTime.StartTime.Minute, meetingTime.EndTime.Hour, meetingTime.EndTime.Minute))
+	}
+
+	return strings.Join(formatted, ", ")
+}
+
+// Formats the meeting times and dates of a class for people
+func formatClassTimes(class Class) string {
+	return fmt.Sprintf("%s (%d/%d - %d/%d)", formatMeetingTimes(class.MeetingTimes),
+		class.Date.StartMonth, class.Date.StartDay, class.Date.EndMonth, class.Date.EndDay)
+}
+
+// Returns true if both lists have the same meeting times in the same order
+func sameMeetingTimes(meetingTimes1 []MeetingTime, meetingTimes2 []MeetingTime) bool {
+	if len(meetingTimes1) != len(meetingTimes2) {
+		return false
+	}
+
+	for i := range meetingTimes1 {
+		if meetingTimes1[i] != meetingTimes2[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Compares two sections with the same class id
+func diffSection(from Class, to Class) []SectionChange {
+	changes := []SectionChange{}
+
+	change := func(kind SectionChangeKind, before string, after string) {
+		changes = append(changes, SectionChange{to.ClassID, to.CourseName, kind, before, after})
+	}
+
+	if !sameMeetingTimes(from.MeetingTimes, to.MeetingTimes) || from.Date != to.Date {
+		change(SectionTimeChanged, formatClassTimes(from), formatClassTimes(to))
+	}
+
+	if normalizeName(from.Instructor) != normalizeName(to.Instructor) {
+		change(SectionInstructorChanged, from.Instructor, to.Instructor)
+	}
+
+	if from.AvailabilityStatus() != to.AvailabilityStatus() {
+		change(SectionAvailabilityChanged, from.AvailabilityStatus().String(), to.AvailabilityStatus().String())
+	}
+
+	return changes
+}
+
+// Compares two class data snapshots of a school, sections are matched by class id
+func diffClassData(from School, to School) ClassDataDiff {
+	diff := ClassDataDiff{
+		FromTimestamp: from.Timestamp,
+		ToTimestamp:   to.Timestamp,
+		Added:         []Class{},
+		Removed:       []Class{},
+		Changes:       []SectionChange{},
+	}
+
+	fromClasses := map[string]Class{}
+	for _, class := range from.Classes {
+		fromClasses[class.ClassID] = class
+	}
+
+	toClasses := map[string]Class{}
+	for _, class := range to.Classes {
+		toClasses[class.ClassID] = class
+
+		fromClass, ok := fromClasses[class.ClassID]
+		if !ok {
+			diff.Added = append(diff.Added, class)
+			continue
+		}
+
+		diff.Changes = append(diff.Changes, diffSection(fromClass, class)...)
+	}
+
+	for _, class := range from.Classes {
+		if _, ok := toClasses[class.ClassID]; !ok {
+			diff.Removed = append(diff.Removed, class)
+		}
+	}
+
+	sort.SliceStable(diff.Changes, func(i, j int) bool {
+		return diff.Changes[i].ClassID < diff.Changes[j].ClassID
+	})
+
+	return diff
+}
+
+// Returns the class ids of the sections that were removed or changed, the ones students with a saved schedule need to hear about
+func (diff ClassDataDiff) affectedClassIDs() []string {
+	classIDs := []string{}
+	seen := map[string]bool{}
+
+	for _, class := range diff.Removed {
+		if !seen[class.ClassID] {
+			seen[class.ClassID] = true
+			classIDs = append(classIDs, class.ClassID)
+		}
+	}
+
+	for _, change := range diff.Changes {
+		if !seen[change.ClassID] {
+			seen[change.ClassID] = true
+			classIDs = append(classIDs, change.ClassID)
+		}
+	}
+
+	sort.Strings(classIDs)
+
+	return classIDs
+}
+
+// Fetches the class data snapshot of a school with the given timestamp from MongoDB
+func fetchClassDataSnapshot(schoolId string, timestamp int64) (School, error) {
+	schoolConfig, err := lookupSchool(schoolId)
+	if err != nil {
+		return School{}, err
+	}
+
+	// Get collection from database
+	collection, err := db.GetDBCollection("Classes")
+
+	if err != nil {
+		fmt.Println(err)
+		return School{}, errors.New("unable to fetch collection from database")
+	}
+
+	cursor := collection.FindOne(context.TODO(), bson.M{"schoolid": schoolConfig.ClassDataKey, "timestamp": timestamp})
+
+	// Deserialize result
+	var elem School
+	err = cursor.Decode(&elem)
+
+	if err != nil {
+		return School{}, errors.New("did not find specified document")
+	}
+
+	return elem, nil
+}
+
+// Fetches the most recent class data snapshots of a school from MongoDB, newest first
+func fetchRecentClassData(schoolId string, count int64) ([]School, error) {
+	schoolConfig, err := lookupSchool(schoolId)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get collection from database
+	collection, err := db.GetDBCollection("Classes")
+
+	if err != nil {
+		fmt.Println(err)
+		return nil, errors.New("unable to fetch collection from database")
+	}
+
+	opts := options.Find().SetSort(bson.M{"$natural": -1}).SetLimit(count) // starts searching from most recent documents
+	cursor, err := collection.Find(context.TODO(), bson.M{"schoolid": schoolConfig.ClassDataKey}, opts)
+	if err != nil {
+		return nil, errors.New("unable to fetch class data")
+	}
+
+	// Deserialize result
+	var elems []School
+	err = cursor.All(context.TODO(), &elems)
+
+	if err != nil {
+		return nil, errors.New("unable to decode class data")
+	}
+
+	return elems, nil
+}
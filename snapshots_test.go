@@ -0,0 +1,73 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffClassData(t *testing.T) {
+	mw := []MeetingTime{testMeeting("MW", Time{8, 0}, Time{9, 0})}
+	tr := []MeetingTime{testMeeting("TR", Time{8, 0}, Time{9, 0})}
+	spring := DateRange{2, 1, 5, 30}
+
+	section := func(classID string, instructor string, meetingTimes []MeetingTime, enrolled int) Class {
+		return Class{ClassID: classID, CourseName: "MATH 5A", Instructor: instructor, MeetingTimes: meetingTimes,
+			Date: spring, Capacity: 30, Enrolled: enrolled, WaitlistCapacity: 5}
+	}
+
+	from := School{Timestamp: 1, Classes: []Class{
+		section("100", "John Smith", mw, 10),
+		section("200", "Jane Doe", mw, 10),
+		section("300", "Mary Jones", mw, 10),
+		section("400", "Ann Lee", mw, 10),
+		section("500", "José Peña", mw, 10),
+	}}
+
+	to := School{Timestamp: 2, Classes: []Class{
+		section("100", "John Smith", mw, 12), // seats taken but still open
+		section("200", "Jane Doe", tr, 30),   // moved and full
+		section("400", "Bob Brown", mw, 10),  // new instructor
+		section("500", "Jose Pena", mw, 10),  // same instructor written without accents
+		section("600", "Ann Lee", tr, 0),     // new section
+	}}
+
+	diff := diffClassData(from, to)
+
+	if diff.FromTimestamp != 1 || diff.ToTimestamp != 2 {
+		t.Errorf("timestamps = %d, %d, want 1, 2", diff.FromTimestamp, diff.ToTimestamp)
+	}
+
+	if len(diff.Added) != 1 || diff.Added[0].ClassID != "600" {
+		t.Errorf("added = %v, want section 600", diff.Added)
+	}
+
+	if len(diff.Removed) != 1 || diff.Removed[0].ClassID != "300" {
+		t.Errorf("removed = %v, want section 300", diff.Removed)
+	}
+
+	wantChanges := []SectionChange{
+		{"200", "MATH 5A", SectionTimeChanged, "MW 08:00-09:00 (2/1 - 5/30)", "TR 08:00-09:00 (2/1 - 5/30)"},
+		{"200", "MATH 5A", SectionAvailabilityChanged, "open", "waitlisted"},
+		{"400", "MATH 5A", SectionInstructorChanged, "Ann Lee", "Bob Brown"},
+	}
+	if !reflect.DeepEqual(diff.Changes, wantChanges) {
+		t.Errorf("changes = %+v, want %+v", diff.Changes, wantChanges)
+	}
+
+	if got, want := diff.affectedClassIDs(), []string{"200", "300", "400"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("affectedClassIDs = %v, want %v", got, want)
+	}
+}
+
+func TestDiffClassDataUnchanged(t *testing.T) {
+	school := School{Classes: []Class{{ClassID: "100", CourseName: "MATH 5A", Instructor: "John Smith"}}}
+
+	diff := diffClassData(school, school)
+	if len(diff.Added) != 0 || len(diff.Removed) != 0 || len(diff.Changes) != 0 {
+		t.Errorf("diff of a snapshot with itself = %+v, want no differences", diff)
+	}
+
+	if affected := diff.affectedClassIDs(); len(affected) != 0 {
+		t.Errorf("affectedClassIDs = %v, want none", affected)
+	}
+}